@@ -0,0 +1,120 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Weekday is a day of the week for a recurring change window.
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// OutsideWindowAction defines what happens to a mutation outside an open window.
+// +kubebuilder:validation:Enum=Block;Escalate
+type OutsideWindowAction string
+
+const (
+	// OutsideWindowBlock blocks the mutation.
+	OutsideWindowBlock OutsideWindowAction = "Block"
+
+	// OutsideWindowEscalate requests human approval for the mutation.
+	OutsideWindowEscalate OutsideWindowAction = "Escalate"
+)
+
+// RecurringWindow is a weekly period during which mutations are permitted.
+type RecurringWindow struct {
+	// days the window opens on. Empty means every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// start is the local opening time ("HH:MM", 24-hour).
+	// +required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// end is the local closing time ("HH:MM", 24-hour). An end at or before
+	// start closes the window the next day.
+	// +required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// BlackoutPeriod is a change freeze over whole days. It overrides every window.
+type BlackoutPeriod struct {
+	// start is the first frozen date ("YYYY-MM-DD").
+	// +required
+	// +kubebuilder:validation:Pattern=`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`
+	Start string `json:"start"`
+
+	// end is the last frozen date ("YYYY-MM-DD"), inclusive. Defaults to start.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`
+	End string `json:"end,omitempty"`
+
+	// reason is shown when a mutation is blocked (e.g. "Q1 close").
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ChangeWindowSpec defines when agents may mutate infrastructure.
+type ChangeWindowSpec struct {
+	// timezone is the IANA timezone windows and blackouts are evaluated in.
+	// +optional
+	// +kubebuilder:default="UTC"
+	Timezone string `json:"timezone,omitempty"`
+
+	// windows are the recurring periods in which mutations are permitted.
+	// With no windows, mutations are permitted at any time outside blackouts.
+	// +optional
+	Windows []RecurringWindow `json:"windows,omitempty"`
+
+	// blackouts are change freezes in which no mutation is permitted.
+	// +optional
+	Blackouts []BlackoutPeriod `json:"blackouts,omitempty"`
+
+	// outsideWindow is what happens to a mutation outside an open window:
+	// Block, or Escalate for human approval. Blackouts always block.
+	// +optional
+	// +kubebuilder:default=Block
+	OutsideWindow OutsideWindowAction `json:"outsideWindow,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Timezone",type="string",JSONPath=".spec.timezone"
+// +kubebuilder:printcolumn:name="Outside",type="string",JSONPath=".spec.outsideWindow"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ChangeWindow is a cluster-scoped maintenance calendar. Agents and
+// environments reference it by name; read actions run at any time, but
+// non-read actions are only permitted inside one of its windows and never
+// during a blackout.
+type ChangeWindow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	Spec ChangeWindowSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChangeWindowList contains a list of ChangeWindows.
+type ChangeWindowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChangeWindow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChangeWindow{}, &ChangeWindowList{})
+}
@@ -31,6 +31,19 @@ const (
 	AutonomyDestructive AutonomyLevel = "automate-destructive"
 )
 
+// RunMode selects whether an agent's changes are executed or only planned.
+// +kubebuilder:validation:Enum=Execute;Plan
+type RunMode string
+
+const (
+	// RunModeExecute runs every tool call that passes guardrails.
+	RunModeExecute RunMode = "Execute"
+
+	// RunModePlan runs read-tier tools but records mutations as planned
+	// instead of executing them.
+	RunModePlan RunMode = "Plan"
+)
+
 // ModelTier abstracts model capability rather than naming a specific model.
 // +kubebuilder:validation:Enum=fast;standard;reasoning
 type ModelTier string
@@ -72,13 +85,30 @@ const (
 	TimeoutRetry   TimeoutAction = "retry"
 )
 
+// WebhookAuthType selects how inbound webhook requests are authenticated.
+// +kubebuilder:validation:Enum=github;hmac;bearer
+type WebhookAuthType string
+
+const (
+	// WebhookAuthGitHub verifies GitHub's X-Hub-Signature-256 header
+	// ("sha256=" + hex HMAC-SHA256 of the body).
+	WebhookAuthGitHub WebhookAuthType = "github"
+
+	// WebhookAuthHMAC verifies a hex HMAC-SHA256 of the body in a configurable header.
+	WebhookAuthHMAC WebhookAuthType = "hmac"
+
+	// WebhookAuthBearer requires "Authorization: Bearer <token>".
+	WebhookAuthBearer WebhookAuthType = "bearer"
+)
+
 // TriggerType defines what can trigger an agent run.
-// +kubebuilder:validation:Enum=webhook;kubernetes-event
+// +kubebuilder:validation:Enum=webhook;kubernetes-event;alertmanager
 type TriggerType string
 
 const (
 	TriggerWebhook         TriggerType = "webhook"
 	TriggerKubernetesEvent TriggerType = "kubernetes-event"
+	TriggerAlertmanager    TriggerType = "alertmanager"
 )
 
 // SkillSourceType indicates where a skill is loaded from.
@@ -115,15 +145,72 @@ type ScheduleSpec struct {
 	// triggers define event-driven execution.
 	// +optional
 	Triggers []TriggerSpec `json:"triggers,omitempty"`
+
+	// concurrencyPolicy controls what happens when a run is due while a
+	// previous run of this agent is still in flight, as for a CronJob.
+	// +optional
+	// +kubebuilder:default=Forbid
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// maxConcurrentRuns caps parallel runs under the Allow policy. Defaults to
+	// the controller's --max-concurrent-per-agent. Ignored by Forbid and Replace.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentRuns *int32 `json:"maxConcurrentRuns,omitempty"`
+
+	// startingDeadline is how late (e.g. "30m") a scheduled slot may still
+	// start, for instance after controller downtime. Slots older than this
+	// are skipped. Unset means no deadline.
+	// +optional
+	StartingDeadline string `json:"startingDeadline,omitempty"`
+
+	// catchUp controls what happens to slots missed while the controller was
+	// down or the agent was busy: "none" skips them, "once" runs a single
+	// catch-up run for the latest, "all" runs each one in turn.
+	// +optional
+	// +kubebuilder:default=once
+	CatchUp CatchUpPolicy `json:"catchUp,omitempty"`
 }
 
+// CatchUpPolicy defines how missed schedule slots are handled.
+// +kubebuilder:validation:Enum=none;once;all
+type CatchUpPolicy string
+
+const (
+	// CatchUpNone skips missed slots; only an on-time slot starts a run.
+	CatchUpNone CatchUpPolicy = "none"
+
+	// CatchUpOnce runs once for the latest missed slot, skipping earlier ones.
+	CatchUpOnce CatchUpPolicy = "once"
+
+	// CatchUpAll runs once for every missed slot within the starting deadline.
+	CatchUpAll CatchUpPolicy = "all"
+)
+
+// ConcurrencyPolicy defines how overlapping runs of an agent are handled.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyAllow runs overlapping triggers in parallel, up to maxConcurrentRuns.
+	ConcurrencyAllow ConcurrencyPolicy = "Allow"
+
+	// ConcurrencyForbid skips (or, for queued triggers, defers) a run while
+	// another is in flight.
+	ConcurrencyForbid ConcurrencyPolicy = "Forbid"
+
+	// ConcurrencyReplace aborts the in-flight run and starts the new one.
+	ConcurrencyReplace ConcurrencyPolicy = "Replace"
+)
+
 // TriggerSpec defines an event-based trigger.
 type TriggerSpec struct {
 	// type is the trigger kind.
 	// +required
 	Type TriggerType `json:"type"`
 
-	// source identifies the event origin (e.g. "alertmanager").
+	// source identifies the event origin (e.g. "alertmanager"). For webhook and
+	// alertmanager types it is the path segment in /webhook/{source}.
 	// +optional
 	Source string `json:"source,omitempty"`
 
@@ -138,6 +225,34 @@ type TriggerSpec struct {
 	// reasons lists event reasons to match (for kubernetes-event type).
 	// +optional
 	Reasons []string `json:"reasons,omitempty"`
+
+	// auth authenticates inbound requests for this webhook source (for webhook
+	// and alertmanager types).
+	// Requests that fail authentication are rejected with 401.
+	// +optional
+	Auth *WebhookAuthSpec `json:"auth,omitempty"`
+}
+
+// WebhookAuthSpec configures authentication for an inbound webhook source.
+type WebhookAuthSpec struct {
+	// type is the authentication method.
+	// +required
+	Type WebhookAuthType `json:"type"`
+
+	// secretRef is the name of a Secret in the agent's namespace holding the
+	// HMAC shared secret or bearer token.
+	// +required
+	SecretRef string `json:"secretRef"`
+
+	// secretKey is the key within the Secret.
+	// +optional
+	// +kubebuilder:default="secret"
+	SecretKey string `json:"secretKey,omitempty"`
+
+	// header is the request header carrying the signature (for hmac type).
+	// +optional
+	// +kubebuilder:default="X-Signature-256"
+	Header string `json:"header,omitempty"`
 }
 
 // ModelSpec configures the LLM for an agent.
@@ -156,6 +271,53 @@ type ModelSpec struct {
 	// +optional
 	// +kubebuilder:default="120s"
 	Timeout string `json:"timeout,omitempty"`
+
+	// compaction controls how the conversation is shrunk as it approaches
+	// the token budget. Defaults apply when unset.
+	// +optional
+	Compaction *CompactionSpec `json:"compaction,omitempty"`
+
+	// costBudget is the max estimated USD cost per run (e.g. "0.50"), priced
+	// from the ModelTierConfig. Unset means no cost limit.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^\$?[0-9]+(\.[0-9]+)?$`
+	CostBudget string `json:"costBudget,omitempty"`
+}
+
+// CompactionStrategy defines how older tool results are compacted.
+// +kubebuilder:validation:Enum=Summarize;Elide
+type CompactionStrategy string
+
+const (
+	// CompactionSummarize replaces older exchanges with a summary written by
+	// the fast-tier model. It falls back to Elide if no summary can be made.
+	CompactionSummarize CompactionStrategy = "Summarize"
+
+	// CompactionElide keeps older tool calls but replaces their results with
+	// a short placeholder.
+	CompactionElide CompactionStrategy = "Elide"
+)
+
+// CompactionSpec configures conversation compaction.
+type CompactionSpec struct {
+	// strategy is how older tool results are compacted.
+	// +optional
+	// +kubebuilder:default=Summarize
+	Strategy CompactionStrategy `json:"strategy,omitempty"`
+
+	// thresholdTokens is the input size of an LLM call above which the
+	// conversation is compacted before the next call. Defaults to a quarter
+	// of tokenBudget.
+	// +optional
+	// +kubebuilder:validation:Minimum=1000
+	ThresholdTokens int64 `json:"thresholdTokens,omitempty"`
+
+	// keepRecent is how many of the most recent tool-call exchanges are
+	// always kept verbatim.
+	// +optional
+	// +kubebuilder:default=4
+	// +kubebuilder:validation:Minimum=1
+	KeepRecent int32 `json:"keepRecent,omitempty"`
 }
 
 // SkillRef references a skill to load.
@@ -204,6 +366,14 @@ type GuardrailsSpec struct {
 	// +kubebuilder:default=10
 	MaxIterations int32 `json:"maxIterations,omitempty"`
 
+	// maxParallelReads caps how many read-tier tool calls from one model turn
+	// run concurrently. Mutations always run one at a time, in order; 1 runs
+	// every call sequentially.
+	// +optional
+	// +kubebuilder:default=4
+	// +kubebuilder:validation:Minimum=1
+	MaxParallelReads int32 `json:"maxParallelReads,omitempty"`
+
 	// maxRetries is the max retries on transient failure.
 	// +optional
 	// +kubebuilder:default=2
@@ -222,6 +392,18 @@ type GuardrailsSpec struct {
 	// +optional
 	// +kubebuilder:default="30m"
 	ApprovalTimeout string `json:"approvalTimeout,omitempty"`
+
+	// changeWindowRef names a cluster-scoped ChangeWindow. Non-read actions
+	// are only permitted while it is open. Overrides the environment's
+	// changeWindowRef.
+	// +optional
+	ChangeWindowRef string `json:"changeWindowRef,omitempty"`
+
+	// injectionAutonomy lowers the run's autonomy to this level for its
+	// remaining actions once tool output looks like a prompt injection.
+	// Unset keeps the configured autonomy; a level above it has no effect.
+	// +optional
+	InjectionAutonomy AutonomyLevel `json:"injectionAutonomy,omitempty"`
 }
 
 // EscalationSpec configures escalation behaviour.
@@ -261,6 +443,12 @@ type ObservabilitySpec struct {
 	// +optional
 	// +kubebuilder:default="info"
 	LogLevel LogLevel `json:"logLevel,omitempty"`
+
+	// transcript persists each run's full sanitized conversation (messages,
+	// tool calls and untruncated tool outputs) to a ConfigMap referenced
+	// from the run, for debugging and `legator runs replay`.
+	// +optional
+	Transcript bool `json:"transcript,omitempty"`
 }
 
 // ReportingSpec configures what happens on different run outcomes.
@@ -327,6 +515,13 @@ type LegatorAgentSpec struct {
 	// paused stops scheduling without deleting the agent.
 	// +optional
 	Paused bool `json:"paused,omitempty"`
+
+	// mode is Execute (default) or Plan. In Plan mode, mutations that pass
+	// guardrails are recorded as planned instead of executed, and the run's
+	// report is a change plan that can be applied later.
+	// +optional
+	// +kubebuilder:default=Execute
+	Mode RunMode `json:"mode,omitempty"`
 }
 
 // LegatorAgentPhase represents the lifecycle phase of an agent.
@@ -286,6 +286,17 @@ type NamespaceMap struct {
 	Additional map[string][]string `json:"additional,omitempty"`
 }
 
+// RedactionSpec configures what is scrubbed from prompts and tool output
+// before they are sent to the LLM provider, on top of the built-in secret
+// patterns and the environment's own credential values.
+type RedactionSpec struct {
+	// patterns are extra regular expressions (RE2 syntax) whose matches are
+	// replaced with [REDACTED]. A first capture group, if any, is kept, so
+	// "(customer_id=)\d+" redacts only the ID.
+	// +optional
+	Patterns []string `json:"patterns,omitempty"`
+}
+
 // --- Main spec ---
 
 // LegatorEnvironmentSpec defines the site-specific configuration for an agent.
@@ -327,6 +338,16 @@ type LegatorEnvironmentSpec struct {
 	// mcpServers maps named MCP tool servers.
 	// +optional
 	MCPServers map[string]MCPServerSpec `json:"mcpServers,omitempty"`
+
+	// changeWindowRef names a cluster-scoped ChangeWindow applied to every
+	// agent bound to this environment, unless the agent sets its own.
+	// +optional
+	ChangeWindowRef string `json:"changeWindowRef,omitempty"`
+
+	// redaction adds environment-specific redaction to content sent to the
+	// LLM provider.
+	// +optional
+	Redaction *RedactionSpec `json:"redaction,omitempty"`
 }
 
 // LegatorEnvironmentPhase represents the lifecycle phase.
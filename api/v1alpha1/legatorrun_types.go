@@ -21,17 +21,18 @@ import (
 )
 
 // RunTrigger describes what initiated an agent run.
-// +kubebuilder:validation:Enum=scheduled;webhook;manual
+// +kubebuilder:validation:Enum=scheduled;webhook;manual;event
 type RunTrigger string
 
 const (
 	RunTriggerScheduled RunTrigger = "scheduled"
 	RunTriggerWebhook   RunTrigger = "webhook"
 	RunTriggerManual    RunTrigger = "manual"
+	RunTriggerEvent     RunTrigger = "event"
 )
 
 // RunPhase represents the lifecycle phase of an agent run.
-// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Escalated;Blocked
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Escalated;Blocked;Aborted
 type RunPhase string
 
 const (
@@ -41,8 +42,19 @@ const (
 	RunPhaseFailed    RunPhase = "Failed"
 	RunPhaseEscalated RunPhase = "Escalated"
 	RunPhaseBlocked   RunPhase = "Blocked"
+	RunPhaseAborted   RunPhase = "Aborted"
 )
 
+// IsTerminal reports whether the phase is final (no further status changes).
+func (p RunPhase) IsTerminal() bool {
+	switch p {
+	case RunPhaseSucceeded, RunPhaseFailed, RunPhaseEscalated, RunPhaseBlocked, RunPhaseAborted:
+		return true
+	default:
+		return false
+	}
+}
+
 // ActionTier classifies the risk level of a tool action.
 // +kubebuilder:validation:Enum=read;service-mutation;destructive-mutation;data-mutation
 type ActionTier string
@@ -55,7 +67,7 @@ const (
 )
 
 // ActionStatus describes what happened to a tool call.
-// +kubebuilder:validation:Enum=executed;blocked;failed;skipped
+// +kubebuilder:validation:Enum=executed;blocked;failed;skipped;approved;denied;pending-approval;planned
 type ActionStatus string
 
 const (
@@ -66,6 +78,9 @@ const (
 	ActionStatusApproved        ActionStatus = "approved"
 	ActionStatusDenied          ActionStatus = "denied"
 	ActionStatusPendingApproval ActionStatus = "pending-approval"
+
+	// ActionStatusPlanned is a mutation deferred by a Plan-mode run.
+	ActionStatusPlanned ActionStatus = "planned"
 )
 
 // FindingSeverity classifies an agent finding.
@@ -98,6 +113,15 @@ type PreFlightResult struct {
 	// +optional
 	DataProtection string `json:"dataProtection,omitempty"`
 
+	// changeWindowCheck indicates whether a ChangeWindow permitted this action.
+	// +optional
+	ChangeWindowCheck string `json:"changeWindowCheck,omitempty"`
+
+	// preConditionCheck indicates whether the action's Action Sheet
+	// preconditions passed.
+	// +optional
+	PreConditionCheck string `json:"preConditionCheck,omitempty"`
+
 	// reason provides a human-readable explanation when a check fails.
 	// +optional
 	Reason string `json:"reason,omitempty"`
@@ -140,6 +164,21 @@ type ActionRecord struct {
 	// escalation captures escalation details when an action is blocked.
 	// +optional
 	Escalation *ActionEscalation `json:"escalation,omitempty"`
+
+	// args are the tool arguments as JSON, recorded for planned actions so
+	// the plan can be applied later.
+	// +optional
+	Args string `json:"args,omitempty"`
+
+	// rollbackOf is the seq of the action this action rolls back. Set on
+	// rollbacks run after a failed post-check or a failed run.
+	// +optional
+	RollbackOf int32 `json:"rollbackOf,omitempty"`
+
+	// retries is how many times the tool call was retried after a transient
+	// failure.
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
 }
 
 // ActionEscalation records an escalation triggered by a blocked action.
@@ -171,6 +210,11 @@ type GuardrailSummary struct {
 	// +optional
 	EscalationsTriggered int32 `json:"escalationsTriggered,omitempty"`
 
+	// redactions is how many secrets were redacted from prompts and tool
+	// output before they were sent to the LLM provider.
+	// +optional
+	Redactions int32 `json:"redactions,omitempty"`
+
 	// autonomyCeiling is the agent's configured autonomy level.
 	// +optional
 	AutonomyCeiling AutonomyLevel `json:"autonomyCeiling,omitempty"`
@@ -178,6 +222,32 @@ type GuardrailSummary struct {
 	// budgetUsed reports resource consumption.
 	// +optional
 	BudgetUsed *BudgetUsage `json:"budgetUsed,omitempty"`
+
+	// suspectedInjections records tool output that looked like a prompt
+	// injection.
+	// +optional
+	SuspectedInjections []InjectionSignal `json:"suspectedInjections,omitempty"`
+}
+
+// InjectionSignal records tool output that matched prompt-injection patterns.
+type InjectionSignal struct {
+	// seq is the action whose output matched, or 0 for a compaction summary.
+	Seq int32 `json:"seq"`
+
+	// tool is the tool that returned the output, or "compaction-summary".
+	Tool string `json:"tool"`
+
+	// patterns are the injection patterns matched (e.g. "instruction-override",
+	// "role-marker", "tool-call", "delimiter-escape").
+	Patterns []string `json:"patterns"`
+
+	// autonomyLoweredTo is the autonomy the run was lowered to in response,
+	// if it was.
+	// +optional
+	AutonomyLoweredTo AutonomyLevel `json:"autonomyLoweredTo,omitempty"`
+
+	// timestamp is when the output was screened.
+	Timestamp metav1.Time `json:"timestamp"`
 }
 
 // BudgetUsage reports token, iteration, and time consumption.
@@ -209,6 +279,12 @@ type BudgetUsage struct {
 
 // RunFinding records something noteworthy the agent discovered.
 type RunFinding struct {
+	// id identifies the finding across runs. It is derived from the resource
+	// and category (or message), so the same issue reported by later runs
+	// keeps the same ID.
+	// +optional
+	ID string `json:"id,omitempty"`
+
 	// severity classifies the finding.
 	// +required
 	Severity FindingSeverity `json:"severity"`
@@ -217,9 +293,18 @@ type RunFinding struct {
 	// +optional
 	Resource string `json:"resource,omitempty"`
 
+	// category is a short stable label for the kind of issue
+	// (e.g. "crashloop", "cert-expiry").
+	// +optional
+	Category string `json:"category,omitempty"`
+
 	// message is a human-readable description.
 	// +required
 	Message string `json:"message"`
+
+	// remediation is the agent's suggested fix.
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
 }
 
 // UsageSummary records resource consumption for a run.
@@ -249,6 +334,115 @@ type UsageSummary struct {
 	EstimatedCost string `json:"estimatedCost,omitempty"`
 }
 
+// CompactionEvent records one compaction of the run's conversation.
+type CompactionEvent struct {
+	// iteration is the loop iteration after which the conversation was compacted.
+	// +required
+	Iteration int32 `json:"iteration"`
+
+	// timestamp is when the compaction happened.
+	// +required
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// strategy is the strategy actually applied.
+	// +required
+	Strategy CompactionStrategy `json:"strategy"`
+
+	// inputTokens is the input size of the LLM call that crossed the threshold.
+	// +optional
+	InputTokens int64 `json:"inputTokens,omitempty"`
+
+	// exchanges is how many tool-call exchanges were compacted.
+	// +optional
+	Exchanges int32 `json:"exchanges,omitempty"`
+
+	// summaryTokens is the tokens spent writing the summary.
+	// +optional
+	SummaryTokens int64 `json:"summaryTokens,omitempty"`
+
+	// reason explains a fallback from the configured strategy.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ModelSpan records the model that answered a run's LLM calls over a
+// range of iterations.
+type ModelSpan struct {
+	// model is the provider/model that answered.
+	// +required
+	Model string `json:"model"`
+
+	// firstIteration is the first iteration it answered.
+	// +required
+	FirstIteration int32 `json:"firstIteration"`
+
+	// lastIteration is the last iteration it answered.
+	// +required
+	LastIteration int32 `json:"lastIteration"`
+}
+
+// TranscriptRef points to a run's persisted transcript.
+type TranscriptRef struct {
+	// configMap is the ConfigMap (in the run's namespace) holding the transcript.
+	// +required
+	ConfigMap string `json:"configMap"`
+
+	// systemPromptHash is the SHA-256 of the system prompt the run used.
+	// +optional
+	SystemPromptHash string `json:"systemPromptHash,omitempty"`
+
+	// truncated is true if tool outputs were shortened to fit the ConfigMap.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TriggerContext records the event that started a triggered run.
+type TriggerContext struct {
+	// source identifies where the trigger came from (e.g. the webhook source "alertmanager").
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// payload is the event payload, sanitized and size-capped.
+	// It is shown to the agent in its first message.
+	// +optional
+	Payload string `json:"payload,omitempty"`
+
+	// groupKey is the Alertmanager group that fired (for alertmanager triggers).
+	// +optional
+	GroupKey string `json:"groupKey,omitempty"`
+
+	// labels are the labels common to every alert in the group.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// annotations are the annotations common to every alert in the group.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// alerts are the firing alerts in the group.
+	// +optional
+	Alerts []TriggerAlert `json:"alerts,omitempty"`
+}
+
+// TriggerAlert is a single Alertmanager alert in a run's trigger context.
+type TriggerAlert struct {
+	// fingerprint identifies the alert within Alertmanager.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// status is "firing" or "resolved".
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// labels are the alert's labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// annotations are the alert's annotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // --- LegatorRun spec and status ---
 
 // LegatorRunSpec defines the immutable configuration for a run.
@@ -265,14 +459,36 @@ type LegatorRunSpec struct {
 	// +required
 	Trigger RunTrigger `json:"trigger"`
 
-	// modelUsed is the actual provider/model resolved from the tier.
+	// triggerContext records why a triggered run started (e.g. the webhook payload).
+	// +optional
+	TriggerContext *TriggerContext `json:"triggerContext,omitempty"`
+
+	// task is the ad-hoc task requested for a manual run (legator.io/task).
+	// +optional
+	Task string `json:"task,omitempty"`
+
+	// target is the ad-hoc target requested for a manual run (legator.io/target).
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// modelUsed is the actual provider/model resolved from the tier when the
+	// run started. status.modelsUsed records the models that answered.
 	// +optional
 	ModelUsed string `json:"modelUsed,omitempty"`
+
+	// mode is Plan for runs that only planned their mutations.
+	// +optional
+	Mode RunMode `json:"mode,omitempty"`
+
+	// planRef names the Plan-mode run whose planned actions this run applies.
+	// +optional
+	PlanRef string `json:"planRef,omitempty"`
 }
 
 // LegatorRunStatus defines the observed state of an LegatorRun.
-// Once phase reaches a terminal state (Succeeded/Failed/Escalated/Blocked),
-// no field is ever modified again.
+// While Running, actions, guardrails and usage are patched incrementally
+// as the run progresses. Once phase reaches a terminal state
+// (Succeeded/Failed/Escalated/Blocked/Aborted), no field is ever modified again.
 type LegatorRunStatus struct {
 	// phase is the current lifecycle phase.
 	// +optional
@@ -290,6 +506,12 @@ type LegatorRunStatus struct {
 	// +optional
 	Usage *UsageSummary `json:"usage,omitempty"`
 
+	// modelsUsed records the models that answered the run's LLM calls, in
+	// iteration order. A run that fell back to another model lists each
+	// model with the iterations it answered.
+	// +optional
+	ModelsUsed []ModelSpan `json:"modelsUsed,omitempty"`
+
 	// actions is the ordered list of every tool call attempted.
 	// +optional
 	Actions []ActionRecord `json:"actions,omitempty"`
@@ -302,10 +524,25 @@ type LegatorRunStatus struct {
 	// +optional
 	Findings []RunFinding `json:"findings,omitempty"`
 
+	// compactions records each time the conversation was compacted to stay
+	// within the token budget.
+	// +optional
+	Compactions []CompactionEvent `json:"compactions,omitempty"`
+
 	// report is the agent's human-readable summary.
 	// +optional
 	Report string `json:"report,omitempty"`
 
+	// transcript references the run's full transcript, when the agent has
+	// observability.transcript enabled.
+	// +optional
+	Transcript *TranscriptRef `json:"transcript,omitempty"`
+
+	// abortedBy records who aborted the run (API user, CLI user, or the
+	// value of the legator.io/abort annotation). Set only when phase is Aborted.
+	// +optional
+	AbortedBy string `json:"abortedBy,omitempty"`
+
 	// conditions represent the current state.
 	// +listType=map
 	// +listMapKey=type
@@ -85,6 +85,48 @@ type TierMapping struct {
 	// costPerMillionOutput is the estimated cost per million output tokens (USD).
 	// +optional
 	CostPerMillionOutput string `json:"costPerMillionOutput,omitempty"`
+
+	// fallbacks are tried in order when a call to this tier's model fails
+	// (after the provider's own retries) or its circuit breaker is open.
+	// +optional
+	Fallbacks []ModelFallback `json:"fallbacks,omitempty"`
+}
+
+// ModelFallback is an alternative model for a tier. Set either tier, to use
+// another tier's mapping (without its fallbacks), or provider and model.
+type ModelFallback struct {
+	// tier names another tier whose mapping to fall back to (e.g. "standard").
+	// +optional
+	Tier ModelTier `json:"tier,omitempty"`
+
+	// provider is the LLM provider name.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// model is the specific model ID.
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// endpoint is the API base URL.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// auth configures authentication for this fallback.
+	// If unset, inherits from the top-level defaultAuth.
+	// +optional
+	Auth *ProviderAuthSpec `json:"auth,omitempty"`
+
+	// maxTokens is the max output tokens for this fallback.
+	// +optional
+	MaxTokens int32 `json:"maxTokens,omitempty"`
+
+	// costPerMillionInput is the estimated cost per million input tokens (USD).
+	// +optional
+	CostPerMillionInput string `json:"costPerMillionInput,omitempty"`
+
+	// costPerMillionOutput is the estimated cost per million output tokens (USD).
+	// +optional
+	CostPerMillionOutput string `json:"costPerMillionOutput,omitempty"`
 }
 
 // ModelTierConfigSpec defines the tier-to-model mappings.
@@ -0,0 +1,95 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunRequestPhase tracks a queued trigger.
+// +kubebuilder:validation:Enum=Pending;Failed
+type RunRequestPhase string
+
+const (
+	RunRequestPhasePending RunRequestPhase = "Pending"
+	RunRequestPhaseFailed  RunRequestPhase = "Failed"
+)
+
+// RunRequestSpec is a trigger waiting to start an agent run.
+type RunRequestSpec struct {
+	// agentRef is the name of the LegatorAgent to run (same namespace).
+	// +required
+	AgentRef string `json:"agentRef"`
+
+	// trigger is what initiated the request.
+	// +required
+	Trigger RunTrigger `json:"trigger"`
+
+	// triggerContext is passed to the run unchanged.
+	// +optional
+	TriggerContext *TriggerContext `json:"triggerContext,omitempty"`
+}
+
+// RunRequestStatus tracks delivery attempts.
+type RunRequestStatus struct {
+	// phase is Pending until the request is dispatched (and deleted), or
+	// Failed once retries are exhausted. Empty means Pending.
+	// +optional
+	Phase RunRequestPhase `json:"phase,omitempty"`
+
+	// attempts is how many times dispatch has been tried.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// nextAttemptTime is the earliest time of the next dispatch attempt.
+	// +optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+
+	// lastError is why the last attempt did not start a run.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Agent",type="string",JSONPath=".spec.agentRef"
+// +kubebuilder:printcolumn:name="Trigger",type="string",JSONPath=".spec.trigger"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Attempts",type="integer",JSONPath=".status.attempts"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RunRequest is a durable, queued trigger for an agent run. Webhook,
+// Alertmanager and Kubernetes event triggers are persisted as RunRequests
+// and drained by the scheduler, so bursts and controller restarts don't
+// lose them. A RunRequest is deleted once its run starts.
+type RunRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	Spec RunRequestSpec `json:"spec"`
+
+	// +optional
+	Status RunRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RunRequestList contains a list of RunRequests.
+type RunRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RunRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RunRequest{}, &RunRequestList{})
+}
@@ -371,6 +371,21 @@ func (in *ApprovalRequestStatus) DeepCopy() *ApprovalRequestStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutPeriod) DeepCopyInto(out *BlackoutPeriod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutPeriod.
+func (in *BlackoutPeriod) DeepCopy() *BlackoutPeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutPeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BudgetUsage) DeepCopyInto(out *BudgetUsage) {
 	*out = *in
@@ -411,6 +426,91 @@ func (in *CapabilitiesSpec) DeepCopy() *CapabilitiesSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeWindow) DeepCopyInto(out *ChangeWindow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeWindow.
+func (in *ChangeWindow) DeepCopy() *ChangeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChangeWindow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeWindowList) DeepCopyInto(out *ChangeWindowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChangeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeWindowList.
+func (in *ChangeWindowList) DeepCopy() *ChangeWindowList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeWindowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChangeWindowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeWindowSpec) DeepCopyInto(out *ChangeWindowSpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]RecurringWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Blackouts != nil {
+		in, out := &in.Blackouts, &out.Blackouts
+		*out = make([]BlackoutPeriod, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeWindowSpec.
+func (in *ChangeWindowSpec) DeepCopy() *ChangeWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
 	*out = *in
@@ -426,6 +526,37 @@ func (in *ChannelSpec) DeepCopy() *ChannelSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactionEvent) DeepCopyInto(out *CompactionEvent) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactionEvent.
+func (in *CompactionEvent) DeepCopy() *CompactionEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactionEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactionSpec) DeepCopyInto(out *CompactionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactionSpec.
+func (in *CompactionSpec) DeepCopy() *CompactionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionSpec) DeepCopyInto(out *ConnectionSpec) {
 	*out = *in
@@ -585,6 +716,13 @@ func (in *GuardrailSummary) DeepCopyInto(out *GuardrailSummary) {
 		*out = new(BudgetUsage)
 		**out = **in
 	}
+	if in.SuspectedInjections != nil {
+		in, out := &in.SuspectedInjections, &out.SuspectedInjections
+		*out = make([]InjectionSignal, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailSummary.
@@ -647,6 +785,27 @@ func (in *HeadscaleConnectivity) DeepCopy() *HeadscaleConnectivity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionSignal) DeepCopyInto(out *InjectionSignal) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionSignal.
+func (in *InjectionSignal) DeepCopy() *InjectionSignal {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionSignal)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeconfigRef) DeepCopyInto(out *KubeconfigRef) {
 	*out = *in
@@ -725,7 +884,7 @@ func (in *LegatorAgentList) DeepCopyObject() runtime.Object {
 func (in *LegatorAgentSpec) DeepCopyInto(out *LegatorAgentSpec) {
 	*out = *in
 	in.Schedule.DeepCopyInto(&out.Schedule)
-	out.Model = in.Model
+	in.Model.DeepCopyInto(&out.Model)
 	if in.Skills != nil {
 		in, out := &in.Skills, &out.Skills
 		*out = make([]SkillRef, len(*in))
@@ -904,6 +1063,11 @@ func (in *LegatorEnvironmentSpec) DeepCopyInto(out *LegatorEnvironmentSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Redaction != nil {
+		in, out := &in.Redaction, &out.Redaction
+		*out = new(RedactionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LegatorEnvironmentSpec.
@@ -948,7 +1112,7 @@ func (in *LegatorRun) DeepCopyInto(out *LegatorRun) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1005,6 +1169,11 @@ func (in *LegatorRunList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LegatorRunSpec) DeepCopyInto(out *LegatorRunSpec) {
 	*out = *in
+	if in.TriggerContext != nil {
+		in, out := &in.TriggerContext, &out.TriggerContext
+		*out = new(TriggerContext)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LegatorRunSpec.
@@ -1033,6 +1202,11 @@ func (in *LegatorRunStatus) DeepCopyInto(out *LegatorRunStatus) {
 		*out = new(UsageSummary)
 		**out = **in
 	}
+	if in.ModelsUsed != nil {
+		in, out := &in.ModelsUsed, &out.ModelsUsed
+		*out = make([]ModelSpan, len(*in))
+		copy(*out, *in)
+	}
 	if in.Actions != nil {
 		in, out := &in.Actions, &out.Actions
 		*out = make([]ActionRecord, len(*in))
@@ -1050,6 +1224,18 @@ func (in *LegatorRunStatus) DeepCopyInto(out *LegatorRunStatus) {
 		*out = make([]RunFinding, len(*in))
 		copy(*out, *in)
 	}
+	if in.Compactions != nil {
+		in, out := &in.Compactions, &out.Compactions
+		*out = make([]CompactionEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Transcript != nil {
+		in, out := &in.Transcript, &out.Transcript
+		*out = new(TranscriptRef)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -1089,9 +1275,29 @@ func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSpan) DeepCopyInto(out *ModelSpan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpan.
+func (in *ModelSpan) DeepCopy() *ModelSpan {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSpan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
 	*out = *in
+	if in.Compaction != nil {
+		in, out := &in.Compaction, &out.Compaction
+		*out = new(CompactionSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
@@ -1104,6 +1310,26 @@ func (in *ModelSpec) DeepCopy() *ModelSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelFallback) DeepCopyInto(out *ModelFallback) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ProviderAuthSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelFallback.
+func (in *ModelFallback) DeepCopy() *ModelFallback {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelFallback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelTierConfig) DeepCopyInto(out *ModelTierConfig) {
 	*out = *in
@@ -1347,6 +1573,46 @@ func (in *ProviderAuthSpec) DeepCopy() *ProviderAuthSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecurringWindow) DeepCopyInto(out *RecurringWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecurringWindow.
+func (in *RecurringWindow) DeepCopy() *RecurringWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(RecurringWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedactionSpec) DeepCopyInto(out *RedactionSpec) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedactionSpec.
+func (in *RedactionSpec) DeepCopy() *RedactionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedactionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReportingSpec) DeepCopyInto(out *ReportingSpec) {
 	*out = *in
@@ -1377,6 +1643,104 @@ func (in *RunFinding) DeepCopy() *RunFinding {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunRequest) DeepCopyInto(out *RunRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRequest.
+func (in *RunRequest) DeepCopy() *RunRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunRequestList) DeepCopyInto(out *RunRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RunRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRequestList.
+func (in *RunRequestList) DeepCopy() *RunRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunRequestSpec) DeepCopyInto(out *RunRequestSpec) {
+	*out = *in
+	if in.TriggerContext != nil {
+		in, out := &in.TriggerContext, &out.TriggerContext
+		*out = new(TriggerContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRequestSpec.
+func (in *RunRequestSpec) DeepCopy() *RunRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunRequestStatus) DeepCopyInto(out *RunRequestStatus) {
+	*out = *in
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRequestStatus.
+func (in *RunRequestStatus) DeepCopy() *RunRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduleSpec) DeepCopyInto(out *ScheduleSpec) {
 	*out = *in
@@ -1387,6 +1751,11 @@ func (in *ScheduleSpec) DeepCopyInto(out *ScheduleSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxConcurrentRuns != nil {
+		in, out := &in.MaxConcurrentRuns, &out.MaxConcurrentRuns
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleSpec.
@@ -1438,6 +1807,13 @@ func (in *TierMapping) DeepCopyInto(out *TierMapping) {
 		*out = new(ProviderAuthSpec)
 		**out = **in
 	}
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make([]ModelFallback, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TierMapping.
@@ -1450,6 +1826,86 @@ func (in *TierMapping) DeepCopy() *TierMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TranscriptRef) DeepCopyInto(out *TranscriptRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TranscriptRef.
+func (in *TranscriptRef) DeepCopy() *TranscriptRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TranscriptRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerAlert) DeepCopyInto(out *TriggerAlert) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerAlert.
+func (in *TriggerAlert) DeepCopy() *TriggerAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriggerContext) DeepCopyInto(out *TriggerContext) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make([]TriggerAlert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerContext.
+func (in *TriggerContext) DeepCopy() *TriggerContext {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerContext)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TriggerSpec) DeepCopyInto(out *TriggerSpec) {
 	*out = *in
@@ -1463,6 +1919,11 @@ func (in *TriggerSpec) DeepCopyInto(out *TriggerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(WebhookAuthSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerSpec.
@@ -1519,3 +1980,18 @@ func (in *VaultCredentialSpec) DeepCopy() *VaultCredentialSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookAuthSpec) DeepCopyInto(out *WebhookAuthSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookAuthSpec.
+func (in *WebhookAuthSpec) DeepCopy() *WebhookAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
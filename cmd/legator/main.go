@@ -17,6 +17,9 @@ You may obtain a copy of the License at
 //	legator agents get <name>       — show agent details
 //	legator runs list [--agent X]   — list recent runs
 //	legator runs logs <name>        — show run audit trail
+//	legator runs abort <name>       — abort an in-flight run
+//	legator runs replay <name>      — re-check a run against current guardrails
+//	legator runs apply <plan>       — execute the changes proposed by a plan run
 //	legator status                  — cluster summary
 //	legator version                 — version info
 package main
@@ -144,6 +147,7 @@ Usage:
   legator run <agent> [options]     Trigger an ad-hoc agent run
     --target <device>               Target device
     --task "description"            Task description
+    --plan                          Propose changes without executing them
     --wait                          Wait for completion
   legator check <target>            Quick health check (via watchman-light)
   legator login [options]           OIDC device-code login for API access
@@ -154,6 +158,11 @@ Usage:
   legator inventory show <name>     Show endpoint details
   legator runs list [--agent X]     List recent runs
   legator runs logs <name>          Show run report/audit trail
+  legator runs abort <name>         Abort an in-flight run
+  legator runs replay <name>        Replay a run's transcript through current guardrails
+    -f, --agent-file <file>         Use guardrails from a local agent manifest
+    --fail-on-change                Exit 2 if any decision differs
+  legator runs apply <plan> [--yes] Execute the changes proposed by a plan run
   legator approvals                 List pending approvals
   legator approve <name> [reason]   Approve an action
   legator deny <name> [reason]      Deny an action
@@ -370,7 +379,7 @@ func agentsGetViaAPI(apiClient *legatorAPIClient, name string) {
 
 func handleRuns(args []string) {
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "Usage: legator runs <list|logs> [args]")
+		fmt.Fprintln(os.Stderr, "Usage: legator runs <list|logs|abort|replay|apply> [args]")
 		os.Exit(1)
 	}
 
@@ -383,6 +392,24 @@ func handleRuns(args []string) {
 			os.Exit(1)
 		}
 		runsLogs(args[1], args[2:])
+	case "abort", "cancel":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: legator runs abort <name>")
+			os.Exit(1)
+		}
+		runsAbort(args[1], args[2:])
+	case "replay":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: legator runs replay <name> [-f agent.yaml] [--fail-on-change]")
+			os.Exit(1)
+		}
+		runsReplay(args[1], args[2:])
+	case "apply":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "Usage: legator runs apply <plan-run> [--yes]")
+			os.Exit(1)
+		}
+		runsApply(args[1], args[2:])
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown runs subcommand: %s\n", args[0])
 		os.Exit(1)
@@ -457,6 +484,8 @@ func runsList(args []string) {
 			phaseIcon = "❌"
 		case "Running":
 			phaseIcon = "🔄"
+		case "Aborted":
+			phaseIcon = "⛔"
 		}
 
 		_, _ = fmt.Fprintf(w, "%s\t%s\t%s %s\t%s\t%d\t%s\t%s\n",
@@ -540,11 +569,16 @@ func runsLogs(name string, args []string) {
 				statusIcon = "🚫"
 			} else if status == "skipped" {
 				statusIcon = "⏭️"
+			} else if status == "planned" {
+				statusIcon = "📝"
 			} else if status == "error" {
 				statusIcon = "⚠️"
 			}
 
 			fmt.Printf("  %d. %s %s %s", i+1, statusIcon, tool, target)
+			if of, _, _ := unstructured.NestedInt64(am, "rollbackOf"); of > 0 {
+				fmt.Printf(" (rollback of #%d)", of)
+			}
 			if status == "blocked" {
 				reason, _ := am["blockReason"].(string)
 				fmt.Printf(" — %s", reason)
@@ -610,6 +644,8 @@ func runsListViaAPI(apiClient *legatorAPIClient, args []string) {
 			phaseIcon = "❌"
 		case "Running":
 			phaseIcon = "🔄"
+		case "Aborted":
+			phaseIcon = "⛔"
 		}
 
 		age := formatTimeAgo(asString(run["createdAt"]))
@@ -636,6 +672,62 @@ func runsListViaAPI(apiClient *legatorAPIClient, args []string) {
 	}
 }
 
+// runsAbort requests cancellation of an in-flight run by setting the
+// legator.io/abort annotation (directly, or via the API when logged in).
+func runsAbort(name string, args []string) {
+	if apiClient, ok, err := tryAPIClient(); err != nil {
+		fatal(err)
+	} else if ok {
+		var resp struct {
+			AbortedBy string `json:"abortedBy"`
+		}
+		if err := apiClient.postJSON("/api/v1/runs/"+url.PathEscape(name)+"/abort", nil, &resp); err != nil {
+			fatal(err)
+		}
+		fmt.Printf("⛔ Abort requested: %s (by %s)\n", name, resp.AbortedBy)
+		return
+	}
+
+	dc, defaultNS, err := getClient()
+	fatal(err)
+
+	ns := getNamespace(args)
+	if ns == "" {
+		ns = defaultNS
+	}
+	if ns == "" {
+		ns = "agents"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	run, err := dc.Resource(runGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	fatal(err)
+
+	switch phase := getNestedString(*run, "status", "phase"); phase {
+	case "Succeeded", "Failed", "Escalated", "Blocked", "Aborted":
+		fmt.Fprintf(os.Stderr, "Run %q already finished (phase %s)\n", name, phase)
+		os.Exit(1)
+	}
+
+	by := "legator-cli"
+	if u := os.Getenv("USER"); u != "" {
+		by = "cli:" + u
+	}
+	annotations := run.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["legator.io/abort"] = by
+	run.SetAnnotations(annotations)
+
+	_, err = dc.Resource(runGVR).Namespace(ns).Update(ctx, run, metav1.UpdateOptions{})
+	fatal(err)
+
+	fmt.Printf("⛔ Abort requested: %s (by %s)\n", name, by)
+}
+
 func runsLogsViaAPI(apiClient *legatorAPIClient, name string) {
 	var runObj map[string]any
 	if err := apiClient.getJSON("/api/v1/runs/"+url.PathEscape(name), &runObj); err != nil {
@@ -686,11 +778,16 @@ func runsLogsViaAPI(apiClient *legatorAPIClient, name string) {
 				statusIcon = "🚫"
 			case "skipped":
 				statusIcon = "⏭️"
+			case "planned":
+				statusIcon = "📝"
 			case "error":
 				statusIcon = "⚠️"
 			}
 
 			fmt.Printf("  %d. %s %s %s", i+1, statusIcon, tool, target)
+			if of, _, _ := unstructured.NestedInt64(am, "rollbackOf"); of > 0 {
+				fmt.Printf(" (rollback of #%d)", of)
+			}
 			if status == "blocked" {
 				reason, _ := am["blockReason"].(string)
 				fmt.Printf(" — %s", reason)
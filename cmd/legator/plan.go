@@ -0,0 +1,125 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// runsApply handles "legator runs apply <plan-run> [--yes]". It shows the
+// planned actions of a Plan-mode run and, once confirmed, triggers a run of
+// the same agent that executes them.
+func runsApply(name string, args []string) {
+	yes := false
+	for _, arg := range args {
+		if arg == "--yes" || arg == "-y" {
+			yes = true
+		}
+	}
+
+	apiClient, viaAPI, err := tryAPIClient()
+	fatal(err)
+
+	var run unstructured.Unstructured
+	if viaAPI {
+		if err := apiClient.getJSON("/api/v1/runs/"+url.PathEscape(name), &run.Object); err != nil {
+			fatal(err)
+		}
+	} else {
+		dc, defaultNS, err := getClient()
+		fatal(err)
+		ns := getNamespace(args)
+		if ns == "" {
+			ns = defaultNS
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		got, err := dc.Resource(runGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+		fatal(err)
+		run = *got
+	}
+
+	agentName := getNestedString(run, "spec", "agentRef")
+	if mode := getNestedString(run, "spec", "mode"); mode != "Plan" {
+		fatal(fmt.Errorf("run %s is not a plan (start one with 'legator run %s --plan')", name, agentName))
+	}
+	if phase := getNestedString(run, "status", "phase"); phase != "Succeeded" {
+		fatal(fmt.Errorf("plan %s is %s; only Succeeded plans can be applied", name, phase))
+	}
+	if by := run.GetAnnotations()["legator.io/applied-by"]; by != "" {
+		fatal(fmt.Errorf("plan %s was already applied by %s", name, by))
+	}
+
+	actions, _, _ := unstructured.NestedSlice(run.Object, "status", "actions")
+	planned := 0
+	fmt.Printf("Plan %s (agent %s, autonomy %s):\n", name, agentName,
+		getNestedString(run, "status", "guardrails", "autonomyCeiling"))
+	for _, a := range actions {
+		am, ok := a.(map[string]any)
+		if !ok || am["status"] != "planned" {
+			continue
+		}
+		planned++
+		fmt.Printf("  %d. %s %s [%s]\n", planned, am["tool"], am["target"], am["tier"])
+	}
+	if planned == 0 {
+		fatal(fmt.Errorf("plan %s has no planned actions", name))
+	}
+
+	if !yes {
+		fmt.Printf("\nApply these %d actions now? Guardrails are checked again before each one. [y/N]: ", planned)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Not applied.")
+			return
+		}
+	}
+
+	if viaAPI {
+		var resp map[string]any
+		path := "/api/v1/agents/" + url.PathEscape(agentName) + "/run"
+		if err := apiClient.postJSON(path, map[string]any{"applyPlan": name}, &resp); err != nil {
+			fatal(err)
+		}
+	} else {
+		dc, defaultNS, err := getClient()
+		fatal(err)
+		ns := getNamespace(args)
+		if ns == "" {
+			ns = defaultNS
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		agent, err := dc.Resource(agentGVR).Namespace(ns).Get(ctx, agentName, metav1.GetOptions{})
+		fatal(err)
+		annotations := agent.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations["legator.io/run-now"] = "true"
+		annotations["legator.io/apply-plan"] = name
+		agent.SetAnnotations(annotations)
+		_, err = dc.Resource(agentGVR).Namespace(ns).Update(ctx, agent, metav1.UpdateOptions{})
+		fatal(err)
+	}
+
+	fmt.Printf("🚀 Applying plan %s. Use 'legator runs list --agent %s' to follow the run.\n", name, agentName)
+}
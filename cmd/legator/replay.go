@@ -0,0 +1,154 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/runner"
+	"github.com/marcus-qen/legator/internal/transcript"
+)
+
+// runsReplay handles "legator runs replay <name> [-f agent.yaml] [--fail-on-change]".
+// It re-drives a run's recorded conversation through the runner using the
+// agent's current guardrails (or those in a local file), with tools answering
+// from the transcript, and shows where the outcomes differ from what happened.
+func runsReplay(name string, args []string) {
+	agentFile := ""
+	failOnChange := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-f", "--agent-file":
+			if i+1 < len(args) {
+				agentFile = args[i+1]
+				i++
+			}
+		case "--fail-on-change":
+			failOnChange = true
+		}
+	}
+
+	c, defaultNS, err := getControllerClient()
+	fatal(err)
+
+	ns := getNamespace(args)
+	if ns == "" {
+		ns = defaultNS
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	run := &corev1alpha1.LegatorRun{}
+	fatal(c.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, run))
+	if run.Status.Transcript == nil {
+		fatal(fmt.Errorf("run %s has no transcript (enable spec.observability.transcript on the agent)", name))
+	}
+
+	t, err := transcript.Load(ctx, c, ns, run.Status.Transcript.ConfigMap)
+	fatal(err)
+
+	agent := &corev1alpha1.LegatorAgent{}
+	if agentFile != "" {
+		data, err := os.ReadFile(agentFile)
+		fatal(err)
+		fatal(yaml.Unmarshal(data, agent))
+		if agent.Namespace == "" {
+			agent.Namespace = ns
+		}
+	} else {
+		fatal(c.Get(ctx, types.NamespacedName{Namespace: ns, Name: run.Spec.AgentRef}, agent))
+	}
+
+	assembled, err := assembler.New(c).Assemble(ctx, agent)
+	fatal(err)
+	assembled.AddAdHocTask(run.Spec.Task, run.Spec.Target)
+
+	steps := runner.NewRunner(nil, nil, logr.Discard()).Replay(ctx, t, run, agent, assembled)
+
+	fmt.Printf("Run: %s | Agent: %s | Model: %s\n", name, t.Agent, t.Model)
+	if transcript.HashPrompt(assembled.Prompt) != t.SystemPromptHash {
+		fmt.Println("⚠️  The system prompt has changed since this run; the model may not have made the same calls.")
+	}
+	if t.Truncated {
+		fmt.Println("⚠️  Tool outputs in this transcript were truncated to fit storage.")
+	}
+	fmt.Println()
+
+	changed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQ\tTOOL\tTARGET\tRECORDED\tREPLAYED\t")
+	for _, s := range steps {
+		marker := ""
+		replayed := string(s.Replayed)
+		if s.ReplayedStatus != "" {
+			replayed = string(s.ReplayedStatus)
+		}
+		if s.Changed() {
+			changed++
+			marker = "  ← changed"
+			if s.Reason != "" {
+				replayed += " (" + s.Reason + ")"
+			}
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", s.Seq, s.Tool, s.Target, s.RecordedStatus, replayed, marker)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\n%d of %d actions would be decided differently.\n", changed, len(steps))
+	if changed > 0 && failOnChange {
+		os.Exit(2)
+	}
+}
+
+// getControllerClient returns a typed client for the Legator and core APIs.
+func getControllerClient() (client.Client, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, nil)
+
+	restCfg, err := config.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	ns, _, _ := config.Namespace()
+	if ns == "" {
+		ns = "agents" // Legator default namespace
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+
+	c, err := client.New(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create client: %w", err)
+	}
+	return c, ns, nil
+}
@@ -12,16 +12,17 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-// handleRunAgent handles "legator run <agent> [--target X] [--task "..."] [--wait]"
+// handleRunAgent handles "legator run <agent> [--target X] [--task "..."] [--plan] [--wait]"
 func handleRunAgent(args []string) {
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "Usage: legator run <agent> [--target <device>] [--task \"...\"] [--wait]")
+		fmt.Fprintln(os.Stderr, "Usage: legator run <agent> [--target <device>] [--task \"...\"] [--plan] [--wait]")
 		os.Exit(1)
 	}
 
 	agentName := args[0]
 	target := ""
 	task := ""
+	plan := false
 	wait := false
 
 	for i := 1; i < len(args); i++ {
@@ -36,6 +37,8 @@ func handleRunAgent(args []string) {
 				task = args[i+1]
 				i++
 			}
+		case "--plan":
+			plan = true
 		case "--wait", "-w":
 			wait = true
 		}
@@ -44,7 +47,7 @@ func handleRunAgent(args []string) {
 	if apiClient, ok, err := tryAPIClient(); err != nil {
 		fatal(err)
 	} else if ok {
-		handleRunAgentViaAPI(apiClient, agentName, target, task, wait)
+		handleRunAgentViaAPI(apiClient, agentName, target, task, plan, wait)
 		return
 	}
 
@@ -78,6 +81,9 @@ func handleRunAgent(args []string) {
 	if target != "" {
 		annotations["legator.io/target"] = target
 	}
+	if plan {
+		annotations["legator.io/plan"] = "true"
+	}
 	agent.SetAnnotations(annotations)
 
 	_, err = dc.Resource(agentGVR).Namespace(ns).Update(ctx, agent, metav1.UpdateOptions{})
@@ -87,6 +93,9 @@ func handleRunAgent(args []string) {
 	autonomy := getNestedString(*agent, "spec", "guardrails", "autonomy")
 
 	fmt.Printf("%s Triggered %s (autonomy: %s)\n", emoji, agentName, autonomy)
+	if plan {
+		fmt.Println("   Mode: plan (changes are proposed, not executed)")
+	}
 	if task != "" {
 		fmt.Printf("   Task: %s\n", task)
 	}
@@ -153,14 +162,17 @@ func handleRunAgent(args []string) {
 	os.Exit(1)
 }
 
-func handleRunAgentViaAPI(apiClient *legatorAPIClient, agentName, target, task string, wait bool) {
-	payload := map[string]string{}
+func handleRunAgentViaAPI(apiClient *legatorAPIClient, agentName, target, task string, plan, wait bool) {
+	payload := map[string]any{}
 	if task != "" {
 		payload["task"] = task
 	}
 	if target != "" {
 		payload["target"] = target
 	}
+	if plan {
+		payload["plan"] = true
+	}
 
 	var resp map[string]any
 	path := "/api/v1/agents/" + url.PathEscape(agentName) + "/run"
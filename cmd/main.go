@@ -102,6 +102,7 @@ func main() {
 	var drainTimeout string
 	var maxConcurrentCluster int
 	var maxConcurrentPerAgent int
+	var namespaceDailyCostCeiling float64
 	var apiListenAddr string
 	var apiOIDCIssuer string
 	var apiOIDCAudience string
@@ -111,6 +112,8 @@ func main() {
 	var headscaleAPIURL string
 	var headscaleAPIKey string
 	var headscaleSyncInterval time.Duration
+	var llmCassette string
+	var llmCassetteMode string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -146,6 +149,9 @@ func main() {
 		"Cluster-wide maximum simultaneous agent runs.")
 	flag.IntVar(&maxConcurrentPerAgent, "max-concurrent-per-agent", 1,
 		"Per-agent maximum simultaneous runs.")
+	flag.Float64Var(&namespaceDailyCostCeiling, "namespace-daily-cost-ceiling", 0,
+		"Default USD each namespace's agent runs may spend per UTC day (0 = unlimited). "+
+			"The legator.io/daily-cost-ceiling namespace annotation overrides it.")
 	var webhookListenAddr string
 	flag.StringVar(&webhookListenAddr, "webhook-listen-address", ":9443",
 		"The address the webhook trigger endpoint listens on. Set to 0 to disable.")
@@ -167,6 +173,10 @@ func main() {
 		"Headscale API key (enables inventory sync when set with --headscale-api-url).")
 	flag.DurationVar(&headscaleSyncInterval, "headscale-sync-interval", 30*time.Second,
 		"How often to poll Headscale for inventory updates.")
+	flag.StringVar(&llmCassette, "llm-cassette", "",
+		"Cassette file for LLM calls. Model interactions are recorded to it or replayed from it (see --llm-cassette-mode).")
+	flag.StringVar(&llmCassetteMode, "llm-cassette-mode", provider.CassetteReplay,
+		"How --llm-cassette is used: record (call the model and save each interaction) or replay (answer from the cassette only).")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -341,7 +351,9 @@ func main() {
 	// Scheduler (with rate limiting and runner)
 	schedCfg := scheduler.DefaultConfig()
 	schedCfg.MaxConcurrentRuns = maxConcurrentCluster
+	schedCfg.MaxConcurrentPerAgent = maxConcurrentPerAgent
 	sched := scheduler.New(mgr.GetClient(), agentRunner, ctrl.Log, schedCfg)
+	sched.EventInformers = mgr.GetCache()
 	if err := mgr.Add(sched); err != nil {
 		setupLog.Error(err, "Failed to add scheduler")
 		os.Exit(1)
@@ -372,58 +384,115 @@ func main() {
 	}
 	shutdownMgr := lifecycle.NewShutdownManager(sched.RunTrackerRef(), drainDur, ctrl.Log)
 
-	// Provider factory: resolves model tier → LLM provider with API key from Secret
-	providerFactory := func(agent *corev1alpha1.LegatorAgent, mtc *corev1alpha1.ModelTierConfig) (provider.Provider, error) {
-		// Look up the ModelTierConfig if not provided
-		if mtc == nil {
-			mtc = &corev1alpha1.ModelTierConfig{}
-			if err := mgr.GetClient().Get(context.Background(), client.ObjectKey{Name: "default"}, mtc); err != nil {
-				return nil, fmt.Errorf("failed to get ModelTierConfig: %w", err)
-			}
-		}
-		// Find the tier mapping
-		tier := agent.Spec.Model.Tier
-		var tierSpec *corev1alpha1.TierMapping
-		for i := range mtc.Spec.Tiers {
-			if mtc.Spec.Tiers[i].Tier == tier {
-				tierSpec = &mtc.Spec.Tiers[i]
-				break
-			}
-		}
-		if tierSpec == nil {
-			return nil, fmt.Errorf("tier %q not found in ModelTierConfig", tier)
-		}
-		// Resolve API key from defaultAuth secretRef
+	// Model provider: builds the LLM provider for a resolved model, with the
+	// API key from its auth Secret and a circuit breaker shared by all runs
+	modelProvider := func(namespace string, model *resolver.ResolvedModel) (provider.Provider, error) {
 		apiKey := ""
-		if mtc.Spec.DefaultAuth != nil && mtc.Spec.DefaultAuth.SecretRef != "" {
+		if model.Auth != nil && model.Auth.SecretRef != "" {
 			secret := &corev1.Secret{}
-			ns := agent.Namespace
 			if err := mgr.GetClient().Get(context.Background(), client.ObjectKey{
-				Namespace: ns,
-				Name:      mtc.Spec.DefaultAuth.SecretRef,
+				Namespace: namespace,
+				Name:      model.Auth.SecretRef,
 			}, secret); err != nil {
-				return nil, fmt.Errorf("failed to get auth secret %q: %w", mtc.Spec.DefaultAuth.SecretRef, err)
+				return nil, fmt.Errorf("failed to get auth secret %q: %w", model.Auth.SecretRef, err)
 			}
-			key := mtc.Spec.DefaultAuth.SecretKey
+			key := model.Auth.SecretKey
 			if key == "" {
 				key = "api-key"
 			}
 			apiKey = string(secret.Data[key])
 		}
 		cfg := provider.ProviderConfig{
-			Type:   tierSpec.Provider,
+			Type:   model.Provider,
 			APIKey: apiKey,
 		}
-		if tierSpec.Endpoint != "" {
-			cfg.Endpoint = tierSpec.Endpoint
+		if model.Endpoint != "" {
+			cfg.Endpoint = model.Endpoint
 		}
-		switch tierSpec.Provider {
-		case "anthropic":
-			return provider.NewAnthropicProvider(cfg)
-		case "openai":
-			return provider.NewOpenAIProvider(cfg)
+		var p provider.Provider
+		var err error
+		switch {
+		case llmCassette != "":
+			cfg.Type = "cassette"
+			cfg.CassettePath = llmCassette
+			cfg.CassetteMode = llmCassetteMode
+			cfg.CassetteUpstream = model.Provider
+			p, err = provider.NewProvider(cfg)
+		case model.Provider == "anthropic":
+			p, err = provider.NewAnthropicProvider(cfg)
+		case model.Provider == "openai":
+			p, err = provider.NewOpenAIProvider(cfg)
 		default:
-			return nil, fmt.Errorf("unsupported provider: %s", tierSpec.Provider)
+			return nil, fmt.Errorf("unsupported provider: %s", model.Provider)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return provider.WithCircuitBreaker(p, provider.Breaker(model.FullModelString+"@"+model.Endpoint)), nil
+	}
+
+	// Provider factory: resolves model tier → LLM provider
+	providerFactory := func(agent *corev1alpha1.LegatorAgent, mtc *corev1alpha1.ModelTierConfig) (provider.Provider, error) {
+		// Look up the ModelTierConfig if not provided
+		if mtc == nil {
+			mtc = &corev1alpha1.ModelTierConfig{}
+			if err := mgr.GetClient().Get(context.Background(), client.ObjectKey{Name: "default"}, mtc); err != nil {
+				return nil, fmt.Errorf("failed to get ModelTierConfig: %w", err)
+			}
+		}
+		model, err := resolver.ResolveTierFromConfig(mtc, agent.Spec.Model.Tier)
+		if err != nil {
+			return nil, err
+		}
+		return modelProvider(agent.Namespace, model)
+	}
+
+	// Fallback factory: providers for the agent tier's fallback models. A
+	// fallback that can't be built is logged and left out of the chain.
+	fallbackFactory := func(agent *corev1alpha1.LegatorAgent) []runner.ModelFallback {
+		mtc := &corev1alpha1.ModelTierConfig{}
+		if err := mgr.GetClient().Get(context.Background(), client.ObjectKey{Name: "default"}, mtc); err != nil {
+			return nil
+		}
+		models, err := resolver.ResolveFallbacks(mtc, agent.Spec.Model.Tier)
+		if err != nil {
+			setupLog.Error(err, "invalid model fallbacks", "agent", agent.Name)
+			return nil
+		}
+		var fallbacks []runner.ModelFallback
+		for _, model := range models {
+			p, err := modelProvider(agent.Namespace, model)
+			if err != nil {
+				setupLog.Error(err, "fallback model unavailable", "agent", agent.Name, "model", model.FullModelString)
+				continue
+			}
+			fallbacks = append(fallbacks, runner.ModelFallback{Model: model, Provider: p})
+		}
+		return fallbacks
+	}
+
+	// Summarizer factory: the fast-tier model writes compaction summaries
+	summarizerFactory := func(agent *corev1alpha1.LegatorAgent) *runner.Summarizer {
+		mtc := &corev1alpha1.ModelTierConfig{}
+		if err := mgr.GetClient().Get(context.Background(), client.ObjectKey{Name: "default"}, mtc); err != nil {
+			return nil
+		}
+		fast, err := resolver.ResolveTierFromConfig(mtc, corev1alpha1.ModelTierFast)
+		if err != nil {
+			return nil
+		}
+		fastAgent := agent.DeepCopy()
+		fastAgent.Spec.Model.Tier = corev1alpha1.ModelTierFast
+		p, err := providerFactory(fastAgent, mtc)
+		if err != nil {
+			setupLog.Error(err, "fast-tier provider unavailable for compaction summaries", "agent", agent.Name)
+			return nil
+		}
+		return &runner.Summarizer{
+			Provider:            p,
+			Model:               fast.Model,
+			Name:                fast.FullModelString,
+			CostPerMillionInput: fast.CostPerMillionInput,
 		}
 	}
 
@@ -527,7 +596,9 @@ func main() {
 		setupLog.Info("No notification channels configured — notifications disabled")
 	}
 
-	_ = eventBus // Available for future use in runner post-run hooks
+	// Resolved Alertmanager groups are published on the event bus by
+	// whichever replica receives them
+	sched.WebhookHandler().WithEventBus(eventBus)
 
 	// Wire RunConfigFactory into scheduler so scheduled runs get providers + tools
 	sched.RunConfigFactory = func(agent *corev1alpha1.LegatorAgent) (runner.RunConfig, error) {
@@ -537,6 +608,11 @@ func main() {
 			return cfg, fmt.Errorf("provider factory: %w", err)
 		}
 		cfg.Provider = p
+		cfg.Fallbacks = fallbackFactory(agent)
+		cfg.DailyCostCeiling = namespaceDailyCostCeiling
+		// Conversation compaction summaries use the fast tier when one is
+		// configured; without it, compaction elides old tool results instead
+		cfg.Summarizer = summarizerFactory(agent)
 		// Resolve environment for credential-aware HTTP tools
 		var resolvedEnv *resolver.ResolvedEnvironment
 		if agent.Spec.EnvironmentRef != "" {
@@ -675,8 +751,9 @@ func main() {
 		Runner:              agentRunner,
 		ProviderFactory:     providerFactory,
 		ToolRegistryFactory: toolRegistryFactory,
+		RunConfigFactory:    sched.RunConfigFactory,
 		OnReconcile: func(agent *corev1alpha1.LegatorAgent) {
-			sched.RegisterWebhookTriggers(agent)
+			sched.RegisterTriggers(agent)
 		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Failed to create controller", "controller", "LegatorAgent")
@@ -692,6 +769,7 @@ func main() {
 	if err := (&controller.LegatorRunReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		Runner: agentRunner,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Failed to create controller", "controller", "LegatorRun")
 		os.Exit(1)
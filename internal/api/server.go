@@ -58,6 +58,9 @@ type Server struct {
 	inventory InventoryProvider
 	log       logr.Logger
 	mux       *http.ServeMux
+
+	// streamPollInterval is how often run streams re-read the LegatorRun.
+	streamPollInterval time.Duration
 }
 
 // NewServer creates a new API server.
@@ -70,6 +73,8 @@ func NewServer(cfg ServerConfig, k8s client.Client, log logr.Logger) *Server {
 		inventory: cfg.Inventory,
 		log:       log.WithName("api"),
 		mux:       http.NewServeMux(),
+
+		streamPollInterval: time.Second,
 	}
 
 	s.registerRoutes()
@@ -130,6 +135,8 @@ func (s *Server) registerRoutes() {
 	// Runs
 	s.mux.HandleFunc("GET /api/v1/runs", s.handleListRuns)
 	s.mux.HandleFunc("GET /api/v1/runs/{id}", s.handleGetRun)
+	s.mux.HandleFunc("GET /api/v1/runs/{id}/stream", s.handleStreamRun)
+	s.mux.HandleFunc("POST /api/v1/runs/{id}/abort", s.handleAbortRun)
 
 	// Inventory
 	s.mux.HandleFunc("GET /api/v1/inventory", s.handleListInventory)
@@ -227,12 +234,23 @@ func (s *Server) handleRunAgent(w http.ResponseWriter, r *http.Request) {
 		Task     string `json:"task"`
 		Target   string `json:"target,omitempty"`
 		Autonomy string `json:"autonomy,omitempty"`
+		Plan     bool   `json:"plan,omitempty"`
+		// ApplyPlan names a Plan-mode run of this agent to apply.
+		ApplyPlan string `json:"applyPlan,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
 		return
 	}
 
+	// Applying a plan approves its changes
+	if req.ApplyPlan != "" {
+		if d := s.rbacEng.Authorize(r.Context(), user, rbac.ActionApprove, name); !d.Allowed {
+			writeForbidden(w, d.Reason)
+			return
+		}
+	}
+
 	// Set the run-now annotation to trigger a run
 	agent := &corev1alpha1.LegatorAgent{}
 	if err := s.k8s.Get(r.Context(), client.ObjectKey{Name: name, Namespace: "agents"}, agent); err != nil {
@@ -251,6 +269,12 @@ func (s *Server) handleRunAgent(w http.ResponseWriter, r *http.Request) {
 	if req.Target != "" {
 		annotations["legator.io/target"] = req.Target
 	}
+	if req.Plan {
+		annotations["legator.io/plan"] = "true"
+	}
+	if req.ApplyPlan != "" {
+		annotations["legator.io/apply-plan"] = req.ApplyPlan
+	}
 	agent.SetAnnotations(annotations)
 
 	if err := s.k8s.Update(r.Context(), agent); err != nil {
@@ -263,6 +287,8 @@ func (s *Server) handleRunAgent(w http.ResponseWriter, r *http.Request) {
 		"user", user.Email,
 		"task", req.Task,
 		"target", req.Target,
+		"plan", req.Plan,
+		"applyPlan", req.ApplyPlan,
 	)
 
 	writeJSON(w, http.StatusAccepted, map[string]string{
@@ -351,6 +377,143 @@ func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, run)
 }
 
+// handleAbortRun requests cancellation of an in-flight run. It sets the
+// legator.io/abort annotation; the LegatorRun controller cancels the run and
+// the runner finalizes it as Aborted.
+func (s *Server) handleAbortRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	user := auth.UserFromContext(r.Context())
+
+	run := &corev1alpha1.LegatorRun{}
+	if err := s.k8s.Get(r.Context(), client.ObjectKey{Name: id, Namespace: "agents"}, run); err != nil {
+		writeError(w, http.StatusNotFound, "run not found: "+id)
+		return
+	}
+
+	if d := s.rbacEng.Authorize(r.Context(), user, rbac.ActionAbortRun, run.Spec.AgentRef); !d.Allowed {
+		writeForbidden(w, d.Reason)
+		return
+	}
+
+	if run.Status.Phase.IsTerminal() {
+		writeError(w, http.StatusConflict, fmt.Sprintf("run %s already finished (phase %s)", id, run.Status.Phase))
+		return
+	}
+
+	annotations := run.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["legator.io/abort"] = user.Email
+	run.SetAnnotations(annotations)
+
+	if err := s.k8s.Update(r.Context(), run); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to abort run: "+err.Error())
+		return
+	}
+
+	s.log.Info("Run abort requested",
+		"run", id,
+		"agent", run.Spec.AgentRef,
+		"user", user.Email,
+	)
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":    "aborting",
+		"run":       id,
+		"abortedBy": user.Email,
+	})
+}
+
+// runStreamKeepalive is the idle period after which a run stream sends a keepalive comment.
+const runStreamKeepalive = 15 * time.Second
+
+// handleStreamRun follows a run as Server-Sent Events until it reaches a
+// terminal phase or the client disconnects. Events:
+//   - action:   one per ActionRecord, in Seq order, as the runner persists them
+//   - progress: phase, usage and guardrail summary whenever the run changes
+//   - complete: final phase, report and findings (the stream then closes)
+func (s *Server) handleStreamRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	user := auth.UserFromContext(r.Context())
+	if d := s.rbacEng.Authorize(r.Context(), user, rbac.ActionViewRuns, ""); !d.Allowed {
+		writeForbidden(w, d.Reason)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	run := &corev1alpha1.LegatorRun{}
+	if err := s.k8s.Get(r.Context(), client.ObjectKey{Name: id, Namespace: "agents"}, run); err != nil {
+		writeError(w, http.StatusNotFound, "run not found: "+id)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(s.streamPollInterval)
+	defer ticker.Stop()
+
+	sentActions := 0
+	lastVersion := ""
+	lastWrite := time.Now()
+	for {
+		if run.ResourceVersion != lastVersion {
+			lastVersion = run.ResourceVersion
+			lastWrite = time.Now()
+
+			for ; sentActions < len(run.Status.Actions); sentActions++ {
+				writeSSE(w, "action", run.Status.Actions[sentActions])
+			}
+			writeSSE(w, "progress", map[string]interface{}{
+				"phase":      run.Status.Phase,
+				"actions":    len(run.Status.Actions),
+				"usage":      run.Status.Usage,
+				"guardrails": run.Status.Guardrails,
+			})
+		} else if time.Since(lastWrite) >= runStreamKeepalive {
+			// Comment line keeps idle connections open through proxies
+			fmt.Fprint(w, ": keepalive\n\n")
+			lastWrite = time.Now()
+		}
+
+		if run.Status.Phase.IsTerminal() {
+			writeSSE(w, "complete", map[string]interface{}{
+				"phase":    run.Status.Phase,
+				"report":   run.Status.Report,
+				"findings": run.Status.Findings,
+				"usage":    run.Status.Usage,
+			})
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := &corev1alpha1.LegatorRun{}
+		if err := s.k8s.Get(r.Context(), client.ObjectKey{Name: id, Namespace: "agents"}, current); err != nil {
+			if r.Context().Err() == nil {
+				writeSSE(w, "error", map[string]string{"error": "failed to read run: " + err.Error()})
+				flusher.Flush()
+			}
+			return
+		}
+		run = current
+	}
+}
+
 func (s *Server) handleListInventory(w http.ResponseWriter, r *http.Request) {
 	user := auth.UserFromContext(r.Context())
 	if d := s.rbacEng.Authorize(r.Context(), user, rbac.ActionViewInventory, ""); !d.Allowed {
@@ -595,6 +758,13 @@ func (w *statusResponseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Flush lets streaming handlers (SSE) flush through the audit wrapper.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // --- Helpers ---
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -607,6 +777,15 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]string{"error": message})
 }
 
+// writeSSE writes a single Server-Sent Event with a JSON data payload.
+func writeSSE(w http.ResponseWriter, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
 func writeForbidden(w http.ResponseWriter, reason string) {
 	writeJSON(w, http.StatusForbidden, map[string]string{
 		"error":  "forbidden",
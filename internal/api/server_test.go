@@ -1,15 +1,23 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
 	"github.com/marcus-qen/legator/internal/api/auth"
 	"github.com/marcus-qen/legator/internal/api/rbac"
 )
@@ -106,3 +114,159 @@ func TestAuditMiddlewareLogsRequests(t *testing.T) {
 		t.Errorf("status = %d, want %d", rr.Code, http.StatusOK)
 	}
 }
+
+func TestStreamRunEmitsActionsAndComplete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1alpha1.AddToScheme(scheme)
+	run := &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman-abc12", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman"},
+		Status: corev1alpha1.LegatorRunStatus{
+			Phase: corev1alpha1.RunPhaseSucceeded,
+			Actions: []corev1alpha1.ActionRecord{
+				{Seq: 1, Tool: "kubectl.get", Status: corev1alpha1.ActionStatusExecuted},
+				{Seq: 2, Tool: "kubectl.delete", Status: corev1alpha1.ActionStatusBlocked},
+			},
+			Report: "all good",
+		},
+	}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(run).Build()
+
+	srv := NewServer(ServerConfig{
+		Policies: []rbac.UserPolicy{
+			{
+				Name:     "viewer",
+				Subjects: []rbac.SubjectMatcher{{Claim: "email", Value: "viewer@example.com"}},
+				Role:     rbac.RoleViewer,
+			},
+		},
+	}, k8s, logr.Discard())
+
+	token := makeTestJWT(map[string]interface{}{
+		"sub":   "viewer-1",
+		"email": "viewer@example.com",
+		"exp":   float64(time.Now().Add(1 * time.Hour).Unix()),
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/runs/watchman-abc12/stream", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var events []string
+	for _, line := range strings.Split(rr.Body.String(), "\n") {
+		if strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		}
+	}
+	want := []string{"action", "action", "progress", "complete"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if !strings.Contains(rr.Body.String(), `"report":"all good"`) {
+		t.Errorf("complete event missing report: %s", rr.Body.String())
+	}
+}
+
+func TestStreamRunNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1alpha1.AddToScheme(scheme)
+	k8s := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	srv := NewServer(ServerConfig{
+		Policies: []rbac.UserPolicy{
+			{
+				Name:     "viewer",
+				Subjects: []rbac.SubjectMatcher{{Claim: "email", Value: "viewer@example.com"}},
+				Role:     rbac.RoleViewer,
+			},
+		},
+	}, k8s, logr.Discard())
+
+	token := makeTestJWT(map[string]interface{}{
+		"sub":   "viewer-1",
+		"email": "viewer@example.com",
+		"exp":   float64(time.Now().Add(1 * time.Hour).Unix()),
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/runs/missing/stream", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestAbortRunSetsAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1alpha1.AddToScheme(scheme)
+	running := &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman-abc12", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman"},
+		Status:     corev1alpha1.LegatorRunStatus{Phase: corev1alpha1.RunPhaseRunning},
+	}
+	done := &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman-def34", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman"},
+		Status:     corev1alpha1.LegatorRunStatus{Phase: corev1alpha1.RunPhaseSucceeded},
+	}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(running, done).Build()
+
+	srv := NewServer(ServerConfig{
+		Policies: []rbac.UserPolicy{
+			{
+				Name:     "viewer",
+				Subjects: []rbac.SubjectMatcher{{Claim: "email", Value: "viewer@example.com"}},
+				Role:     rbac.RoleViewer,
+			},
+			{
+				Name:     "operator",
+				Subjects: []rbac.SubjectMatcher{{Claim: "email", Value: "operator@example.com"}},
+				Role:     rbac.RoleOperator,
+			},
+		},
+	}, k8s, logr.Discard())
+
+	abort := func(email, run string) int {
+		token := makeTestJWT(map[string]interface{}{
+			"sub":   email,
+			"email": email,
+			"exp":   float64(time.Now().Add(1 * time.Hour).Unix()),
+		})
+		req := httptest.NewRequest("POST", "/api/v1/runs/"+run+"/abort", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := abort("viewer@example.com", "watchman-abc12"); code != http.StatusForbidden {
+		t.Errorf("viewer abort: status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := abort("operator@example.com", "watchman-def34"); code != http.StatusConflict {
+		t.Errorf("abort finished run: status = %d, want %d", code, http.StatusConflict)
+	}
+	if code := abort("operator@example.com", "missing"); code != http.StatusNotFound {
+		t.Errorf("abort missing run: status = %d, want %d", code, http.StatusNotFound)
+	}
+	if code := abort("operator@example.com", "watchman-abc12"); code != http.StatusAccepted {
+		t.Fatalf("operator abort: status = %d, want %d", code, http.StatusAccepted)
+	}
+
+	got := &corev1alpha1.LegatorRun{}
+	if err := k8s.Get(context.Background(), client.ObjectKeyFromObject(running), got); err != nil {
+		t.Fatal(err)
+	}
+	if by := got.GetAnnotations()["legator.io/abort"]; by != "operator@example.com" {
+		t.Errorf("abort annotation = %q, want operator@example.com", by)
+	}
+}
@@ -16,13 +16,17 @@ import (
 	"sort"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
 	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/security"
 	"github.com/marcus-qen/legator/internal/skill"
 )
 
+// +kubebuilder:rbac:groups=legator.io,resources=changewindows,verbs=get;list;watch
+
 // AssembledAgent is the complete output of the assembly process —
 // everything needed to execute an agent run.
 type AssembledAgent struct {
@@ -41,6 +45,13 @@ type AssembledAgent struct {
 	// ActionRegistry maps action IDs to their declarations.
 	ActionRegistry map[string]*skill.Action
 
+	// ChangeWindow gates non-read actions (nil if none is referenced).
+	ChangeWindow *corev1alpha1.ChangeWindow
+
+	// ChangeWindowErr is set if the referenced ChangeWindow couldn't be
+	// read. The run goes ahead, but non-read actions are blocked.
+	ChangeWindowErr error
+
 	// CapabilityCheck is the result of capability validation.
 	CapabilityCheck *resolver.CapabilityCheckResult
 
@@ -76,6 +87,22 @@ func (a *Assembler) Assemble(ctx context.Context, agent *corev1alpha1.LegatorAge
 	}
 	result.Environment = env
 
+	// 1b. Resolve change window (the agent's overrides the environment's)
+	cwName := agent.Spec.Guardrails.ChangeWindowRef
+	if cwName == "" {
+		cwName = env.ChangeWindowRef
+	}
+	if cwName != "" {
+		cw := &corev1alpha1.ChangeWindow{}
+		if err := a.client.Get(ctx, types.NamespacedName{Name: cwName}, cw); err != nil {
+			result.ChangeWindowErr = fmt.Errorf("change window %q resolution failed: %w", cwName, err)
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("%v; non-read actions will be blocked", result.ChangeWindowErr))
+		} else {
+			result.ChangeWindow = cw
+		}
+	}
+
 	// 2. Resolve model tier
 	modelResolver := resolver.NewModelTierResolver(a.client)
 	model, err := modelResolver.Resolve(ctx, agent.Spec.Model.Tier)
@@ -166,10 +193,37 @@ func buildPrompt(
 	b.WriteString("- `agent.run.status`: ok | error\n")
 	b.WriteString("- `agent.run.duration_ms`: wall-clock time\n")
 	b.WriteString("- Summary of actions taken and findings\n")
+	b.WriteString("Report each finding with the `report.finding` tool as you discover it (severity, resource, category, message, remediation) — findings only in your final text may be missed.\n")
 
 	return b.String()
 }
 
+// AddAdHocTask appends an ad-hoc task section to the prompt. Manual runs
+// requested with a task or target (legator run --task/--target, or
+// POST /api/v1/agents/{name}/run) use it to focus the agent on that request.
+func (a *AssembledAgent) AddAdHocTask(task, target string) {
+	if task == "" && target == "" {
+		return
+	}
+	a.Prompt += "\n" + buildAdHocTaskSection(task, target)
+}
+
+// buildAdHocTaskSection creates the ad-hoc task block for manual runs.
+func buildAdHocTaskSection(task, target string) string {
+	var b strings.Builder
+	b.WriteString("## Ad-hoc Task\n")
+	b.WriteString("This run was requested manually by an operator.\n")
+	if task != "" {
+		fmt.Fprintf(&b, "- Task: %s\n", task)
+	}
+	if target != "" {
+		fmt.Fprintf(&b, "- Target: %s\n", target)
+	}
+	b.WriteString("\nFocus on this request instead of your routine checks. ")
+	b.WriteString("Your guardrails and skill instructions still apply.\n")
+	return b.String()
+}
+
 // buildGuardrailsSection creates the guardrails preamble.
 func buildGuardrailsSection(g *corev1alpha1.GuardrailsSpec) string {
 	var b strings.Builder
@@ -210,6 +264,9 @@ func buildGuardrailsSection(g *corev1alpha1.GuardrailsSpec) string {
 		}
 	}
 
+	b.WriteString("\n### Tool Output\n")
+	b.WriteString(security.UntrustedOutputNotice)
+
 	return b.String()
 }
 
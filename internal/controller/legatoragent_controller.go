@@ -33,12 +33,24 @@ import (
 	"github.com/marcus-qen/legator/internal/provider"
 	"github.com/marcus-qen/legator/internal/resolver"
 	"github.com/marcus-qen/legator/internal/runner"
+	"github.com/marcus-qen/legator/internal/scheduler"
 	"github.com/marcus-qen/legator/internal/tools"
 )
 
 const (
 	// AnnotationRunNow triggers a manual agent run when set to "true".
 	AnnotationRunNow = "legator.io/run-now"
+
+	// AnnotationTask and AnnotationTarget carry an ad-hoc task for the next
+	// manual run. They are consumed (removed) together with AnnotationRunNow.
+	AnnotationTask   = "legator.io/task"
+	AnnotationTarget = "legator.io/target"
+
+	// AnnotationPlan ("true") makes the next manual run a Plan-mode run.
+	// AnnotationApplyPlan names a Plan-mode run for the next manual run to
+	// apply. Both are consumed together with AnnotationRunNow.
+	AnnotationPlan      = "legator.io/plan"
+	AnnotationApplyPlan = "legator.io/apply-plan"
 )
 
 // LegatorAgentReconciler reconciles an LegatorAgent object.
@@ -57,8 +69,13 @@ type LegatorAgentReconciler struct {
 	// Nil means manual triggers are disabled.
 	ToolRegistryFactory func(agent *corev1alpha1.LegatorAgent, env *resolver.ResolvedEnvironment) (*tools.Registry, error)
 
+	// RunConfigFactory builds the full RunConfig (provider, tools, Vault
+	// credentials and their Cleanup hook) for manual runs. When nil, manual
+	// runs fall back to ProviderFactory and ToolRegistryFactory.
+	RunConfigFactory func(agent *corev1alpha1.LegatorAgent) (runner.RunConfig, error)
+
 	// OnReconcile is called after successful reconciliation with the agent.
-	// Used to register webhook triggers with the scheduler.
+	// Used to register webhook and kubernetes-event triggers with the scheduler.
 	OnReconcile func(agent *corev1alpha1.LegatorAgent)
 }
 
@@ -94,10 +111,22 @@ func (r *LegatorAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Step 2.25: Check for manual trigger annotation
 	if annotations := agent.GetAnnotations(); annotations != nil {
 		if annotations[AnnotationRunNow] == "true" {
-			log.Info("Manual run triggered via annotation", "agent", agent.Name)
+			log.Info("Manual run triggered via annotation", "agent", agent.Name,
+				"task", annotations[AnnotationTask], "target", annotations[AnnotationTarget])
 
-			// Remove the annotation immediately to prevent re-trigger
+			// Consume the ad-hoc task/target with the trigger so they don't
+			// leak into the next scheduled run
+			task := annotations[AnnotationTask]
+			target := annotations[AnnotationTarget]
+			plan := annotations[AnnotationPlan] == "true"
+			planRef := annotations[AnnotationApplyPlan]
+
+			// Remove the annotations immediately to prevent re-trigger
 			delete(annotations, AnnotationRunNow)
+			delete(annotations, AnnotationTask)
+			delete(annotations, AnnotationTarget)
+			delete(annotations, AnnotationPlan)
+			delete(annotations, AnnotationApplyPlan)
 			agent.SetAnnotations(annotations)
 			if err := r.Update(ctx, agent); err != nil {
 				log.Error(err, "Failed to remove run-now annotation")
@@ -107,15 +136,34 @@ func (r *LegatorAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			// Execute the run if runner is configured
 			if r.Runner != nil {
 				go func() {
+					// Independent of the reconcile context; cancel via Runner.Abort
 					runCtx := context.Background()
 					runLog := log.WithValues("trigger", "manual", "agent", agent.Name)
 
 					cfg := runner.RunConfig{
 						Trigger: corev1alpha1.RunTriggerManual,
+						Task:    task,
+						Target:  target,
+						Plan:    plan,
+						PlanRef: planRef,
+					}
+
+					if r.RunConfigFactory != nil {
+						built, err := r.RunConfigFactory(agent)
+						if err != nil {
+							runLog.Error(err, "Failed to build run config")
+							return
+						}
+						cfg = built
+						cfg.Trigger = corev1alpha1.RunTriggerManual
+						cfg.Task = task
+						cfg.Target = target
+						cfg.Plan = plan
+						cfg.PlanRef = planRef
 					}
 
 					// Create provider if factory is available
-					if r.ProviderFactory != nil {
+					if cfg.Provider == nil && r.ProviderFactory != nil {
 						p, err := r.ProviderFactory(agent, nil)
 						if err != nil {
 							runLog.Error(err, "Failed to create LLM provider")
@@ -125,7 +173,7 @@ func (r *LegatorAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request
 					}
 
 					// Create tool registry if factory is available
-					if r.ToolRegistryFactory != nil {
+					if cfg.ToolRegistry == nil && r.ToolRegistryFactory != nil {
 						// Resolve environment for credential-aware HTTP tools
 						var resolvedEnv *resolver.ResolvedEnvironment
 						if agent.Spec.EnvironmentRef != "" {
@@ -203,6 +251,27 @@ func (r *LegatorAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		})
 	}
 
+	// Validate trigger filters. The scheduler skips triggers whose filter
+	// doesn't compile, so surface the error here rather than failing silently.
+	if err := scheduler.ValidateTriggerFilters(agent.Spec.Schedule.Triggers); err != nil {
+		log.Info("Invalid trigger filter", "error", err.Error())
+		meta.SetStatusCondition(&agent.Status.Conditions, metav1.Condition{
+			Type:               "TriggersValid",
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidFilter",
+			Message:            err.Error(),
+			ObservedGeneration: agent.Generation,
+		})
+	} else {
+		meta.SetStatusCondition(&agent.Status.Conditions, metav1.Condition{
+			Type:               "TriggersValid",
+			Status:             metav1.ConditionTrue,
+			Reason:             "FiltersValid",
+			Message:            "All trigger filters compile",
+			ObservedGeneration: agent.Generation,
+		})
+	}
+
 	// Update status
 	agent.Status.Phase = phase
 	if err := r.Status().Update(ctx, agent); err != nil {
@@ -28,6 +28,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/security"
 )
 
 // LegatorEnvironmentReconciler reconciles an LegatorEnvironment object.
@@ -66,13 +67,24 @@ func (r *LegatorEnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	// Set status to Ready (basic validation — real validation comes in Phase 1)
 	env.Status.Phase = corev1alpha1.LegatorEnvironmentPhaseReady
-	meta.SetStatusCondition(&env.Status.Conditions, metav1.Condition{
+	ready := metav1.Condition{
 		Type:               "Ready",
 		Status:             metav1.ConditionTrue,
 		Reason:             "Reconciled",
 		Message:            "LegatorEnvironment reconciled successfully",
 		ObservedGeneration: env.Generation,
-	})
+	}
+
+	// Runs fail on an invalid redaction pattern; surface it here
+	if env.Spec.Redaction != nil {
+		if _, err := security.NewRedactor(env.Spec.Redaction.Patterns, nil); err != nil {
+			env.Status.Phase = corev1alpha1.LegatorEnvironmentPhaseError
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = "InvalidRedactionPattern"
+			ready.Message = err.Error()
+		}
+	}
+	meta.SetStatusCondition(&env.Status.Conditions, ready)
 
 	if err := r.Status().Update(ctx, env); err != nil {
 		log.Error(err, "Failed to update LegatorEnvironment status")
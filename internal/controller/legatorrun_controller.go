@@ -18,20 +18,35 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/runner"
+)
+
+const (
+	// AnnotationAbort requests that an in-flight run be aborted. The value
+	// records who asked for the abort ("true" is accepted and recorded as "annotation").
+	AnnotationAbort = "legator.io/abort"
 )
 
 // LegatorRunReconciler reconciles an LegatorRun object.
 type LegatorRunReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Runner is consulted to cancel in-flight runs on abort.
+	// Nil means aborts only finalize the LegatorRun record.
+	Runner *runner.Runner
 }
 
 // +kubebuilder:rbac:groups=legator.io,resources=legatorruns,verbs=get;list;watch;create;update;patch;delete
@@ -61,6 +76,11 @@ func (r *LegatorRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		"phase", run.Status.Phase,
 	)
 
+	// Abort requested via annotation (set by the API, CLI, or kubectl)
+	if by, ok := run.GetAnnotations()[AnnotationAbort]; ok && !run.Status.Phase.IsTerminal() {
+		return r.abortRun(ctx, run, by)
+	}
+
 	// In Phase 2+, this reconciler will:
 	// - Enforce immutability of terminal runs (Succeeded/Failed/Escalated/Blocked)
 	// - Update parent LegatorAgent status (lastRunTime, runCount, consecutiveFailures)
@@ -69,6 +89,80 @@ func (r *LegatorRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// orphanGrace is how long past its timeout a run may take to finalize
+// before it is treated as orphaned.
+const orphanGrace = time.Minute
+
+// abortRun cancels the run if it is executing in this process. The runner then
+// finalizes it as Aborted and runs its Cleanup hook. A run that isn't executing
+// here and is provably orphaned (e.g. by a controller restart) has no one left
+// to finalize it, so it is marked Aborted directly. Any other run may still be
+// executing elsewhere, so the abort is retried once it would have timed out.
+func (r *LegatorRunReconciler) abortRun(ctx context.Context, run *corev1alpha1.LegatorRun, by string) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if by == "" || by == "true" {
+		by = "annotation"
+	}
+
+	if r.Runner != nil && r.Runner.Abort(client.ObjectKeyFromObject(run), by) {
+		log.Info("Abort signalled to in-flight run", "run", run.Name, "abortedBy", by)
+		return ctrl.Result{}, nil
+	}
+
+	wait, err := r.untilOrphaned(ctx, run)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if wait > 0 {
+		log.Info("Run is not executing here and may still be running, retrying the abort later",
+			"run", run.Name, "abortedBy", by, "retryIn", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	now := metav1.Now()
+	run.Status.Phase = corev1alpha1.RunPhaseAborted
+	run.Status.AbortedBy = by
+	run.Status.CompletionTime = &now
+	run.Status.Report = fmt.Sprintf("run aborted by %s (not executing in this controller)", by)
+	run.Status.Conditions = []metav1.Condition{{
+		Type:               "Complete",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: now,
+		Reason:             string(corev1alpha1.RunPhaseAborted),
+		Message:            run.Status.Report,
+	}}
+	if err := r.Status().Update(ctx, run); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Orphaned run marked Aborted", "run", run.Name, "abortedBy", by)
+	return ctrl.Result{}, nil
+}
+
+// untilOrphaned returns how long until a run not executing in this process
+// is provably orphaned, or zero if it already is. The runner registers a run
+// before creating it, so a run that isn't Running has no executor; a Running
+// run is orphaned once its agent's timeout and orphanGrace have passed since
+// it started.
+func (r *LegatorRunReconciler) untilOrphaned(ctx context.Context, run *corev1alpha1.LegatorRun) (time.Duration, error) {
+	if run.Status.Phase != corev1alpha1.RunPhaseRunning || run.Status.StartTime == nil {
+		return 0, nil
+	}
+
+	agent := &corev1alpha1.LegatorAgent{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: run.Spec.AgentRef}, agent); err != nil {
+		if errors.IsNotFound(err) {
+			// No agent left to run it
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	deadline := run.Status.StartTime.Add(runner.RunTimeout(agent) + orphanGrace)
+	return max(time.Until(deadline), 0), nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *LegatorRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
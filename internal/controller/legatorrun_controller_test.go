@@ -18,7 +18,9 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -28,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/runner"
 )
 
 var _ = Describe("LegatorRun Controller", func() {
@@ -82,5 +85,49 @@ var _ = Describe("LegatorRun Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should only mark an aborted run that is not executing here Aborted once it is orphaned", func() {
+			agent := &corev1alpha1.LegatorAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+				Spec: corev1alpha1.LegatorAgentSpec{
+					Schedule:       corev1alpha1.ScheduleSpec{Cron: "*/5 * * * *"},
+					Model:          corev1alpha1.ModelSpec{Tier: corev1alpha1.ModelTierFast, TokenBudget: 8000, Timeout: "60s"},
+					Guardrails:     corev1alpha1.GuardrailsSpec{Autonomy: corev1alpha1.AutonomyObserve, MaxIterations: 5},
+					EnvironmentRef: "test-env",
+				},
+			}
+			Expect(k8sClient.Create(ctx, agent)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, agent)).To(Succeed()) }()
+
+			By("starting the run just now, as another process might have")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, run)).To(Succeed())
+			run.Status.Phase = corev1alpha1.RunPhaseRunning
+			run.Status.StartTime = &metav1.Time{Time: time.Now()}
+			Expect(k8sClient.Status().Update(ctx, run)).To(Succeed())
+			run.Annotations = map[string]string{AnnotationAbort: "alice@example.com"}
+			Expect(k8sClient.Update(ctx, run)).To(Succeed())
+
+			controllerReconciler := &LegatorRunReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Runner: runner.NewRunner(k8sClient, nil, logr.Discard()),
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", time.Minute))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, run)).To(Succeed())
+			Expect(run.Status.Phase).To(Equal(corev1alpha1.RunPhaseRunning))
+
+			By("moving the start time past the agent's timeout")
+			run.Status.StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			Expect(k8sClient.Status().Update(ctx, run)).To(Succeed())
+
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, run)).To(Succeed())
+			Expect(run.Status.Phase).To(Equal(corev1alpha1.RunPhaseAborted))
+			Expect(run.Status.AbortedBy).To(Equal("alice@example.com"))
+		})
 	})
 })
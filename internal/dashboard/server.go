@@ -627,6 +627,8 @@ func statusIcon(phase string) string {
 		return "🚫"
 	case "Escalated":
 		return "⚠️"
+	case "Aborted":
+		return "⛔"
 	case "Ready":
 		return "✅"
 	case "Pending":
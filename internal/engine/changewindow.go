@@ -0,0 +1,161 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+// dateLayout is the ChangeWindow blackout date format.
+const dateLayout = "2006-01-02"
+
+// windowState is whether a ChangeWindow permits mutations at a given time.
+type windowState struct {
+	// open is true if mutations are permitted.
+	open bool
+
+	// frozen is true if a blackout (or an invalid ChangeWindow) closed it.
+	frozen bool
+
+	// reason explains why the window is closed.
+	reason string
+}
+
+// evaluateChangeWindow reports whether the ChangeWindow is open at now.
+// Blackouts override windows; an unparseable ChangeWindow is treated as a
+// freeze so mutations fail closed.
+func evaluateChangeWindow(cw *corev1alpha1.ChangeWindow, now time.Time) windowState {
+	loc, err := time.LoadLocation(cw.Spec.Timezone)
+	if cw.Spec.Timezone == "" {
+		loc, err = time.UTC, nil
+	}
+	if err != nil {
+		return windowState{frozen: true, reason: fmt.Sprintf("change window %q has invalid timezone %q", cw.Name, cw.Spec.Timezone)}
+	}
+	local := now.In(loc)
+
+	for _, b := range cw.Spec.Blackouts {
+		frozen, err := inBlackout(b, local)
+		if err != nil {
+			return windowState{frozen: true, reason: fmt.Sprintf("change window %q: %v", cw.Name, err)}
+		}
+		if frozen {
+			end := b.End
+			if end == "" {
+				end = b.Start
+			}
+			reason := fmt.Sprintf("change freeze %q in effect through %s", cw.Name, end)
+			if b.Reason != "" {
+				reason += ": " + b.Reason
+			}
+			return windowState{frozen: true, reason: reason}
+		}
+	}
+
+	if len(cw.Spec.Windows) == 0 {
+		return windowState{open: true}
+	}
+
+	var next time.Time
+	for _, w := range cw.Spec.Windows {
+		open, opens, err := inWindow(w, local)
+		if err != nil {
+			return windowState{frozen: true, reason: fmt.Sprintf("change window %q: %v", cw.Name, err)}
+		}
+		if open {
+			return windowState{open: true}
+		}
+		if !opens.IsZero() && (next.IsZero() || opens.Before(next)) {
+			next = opens
+		}
+	}
+
+	reason := fmt.Sprintf("outside change window %q", cw.Name)
+	if !next.IsZero() {
+		reason += fmt.Sprintf(" (next opens %s)", next.Format(time.RFC3339))
+	}
+	return windowState{reason: reason}
+}
+
+// inBlackout reports whether local falls on a blackout date.
+func inBlackout(b corev1alpha1.BlackoutPeriod, local time.Time) (bool, error) {
+	start, err := time.ParseInLocation(dateLayout, b.Start, local.Location())
+	if err != nil {
+		return false, fmt.Errorf("invalid blackout start %q", b.Start)
+	}
+	end := start
+	if b.End != "" {
+		if end, err = time.ParseInLocation(dateLayout, b.End, local.Location()); err != nil {
+			return false, fmt.Errorf("invalid blackout end %q", b.End)
+		}
+	}
+	return !local.Before(start) && local.Before(end.AddDate(0, 0, 1)), nil
+}
+
+// inWindow reports whether local falls inside a recurring window, and if not,
+// when it next opens (within a week). Windows that end at or before their
+// start run past midnight, so the previous day's occurrence is checked too.
+func inWindow(w corev1alpha1.RecurringWindow, local time.Time) (bool, time.Time, error) {
+	startH, startM, err := parseClock(w.Start)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	endH, endM, err := parseClock(w.End)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	var next time.Time
+	for offset := -1; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		if !windowOnDay(w, day.Weekday()) {
+			continue
+		}
+		opens := time.Date(day.Year(), day.Month(), day.Day(), startH, startM, 0, 0, local.Location())
+		closes := time.Date(day.Year(), day.Month(), day.Day(), endH, endM, 0, 0, local.Location())
+		if !closes.After(opens) {
+			closes = closes.AddDate(0, 0, 1)
+		}
+		if !local.Before(opens) && local.Before(closes) {
+			return true, time.Time{}, nil
+		}
+		if opens.After(local) && (next.IsZero() || opens.Before(next)) {
+			next = opens
+		}
+	}
+	return false, next, nil
+}
+
+// windowOnDay reports whether the window opens on the given weekday.
+func windowOnDay(w corev1alpha1.RecurringWindow, day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	name := corev1alpha1.Weekday(day.String()[:3])
+	for _, d := range w.Days {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses "HH:MM".
+func parseClock(s string) (int, int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid window time %q", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/skill"
+)
+
+func weekdayWindow() *corev1alpha1.ChangeWindow {
+	return &corev1alpha1.ChangeWindow{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod-maintenance"},
+		Spec: corev1alpha1.ChangeWindowSpec{
+			Timezone: "Europe/London",
+			Windows: []corev1alpha1.RecurringWindow{
+				{Days: []corev1alpha1.Weekday{"Mon", "Tue", "Wed", "Thu"}, Start: "22:00", End: "02:00"},
+			},
+			Blackouts: []corev1alpha1.BlackoutPeriod{
+				{Start: "2026-03-30", End: "2026-04-02", Reason: "Q1 close"},
+			},
+		},
+	}
+}
+
+func TestEvaluateChangeWindow(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 3, day, hour, min, 0, 0, london)
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantOpen   bool
+		wantFrozen bool
+	}{
+		{"inside window", at(17, 23, 0), true, false},             // Tue 23:00
+		{"after midnight", at(18, 1, 30), true, false},            // Wed 01:30, Tue's window
+		{"closed at end", at(18, 2, 0), false, false},             // Wed 02:00
+		{"daytime", at(18, 14, 0), false, false},                  // Wed 14:00
+		{"Thu window spills into Fri", at(20, 1, 0), true, false}, // Fri 01:00
+		{"Fri night", at(20, 23, 0), false, false},                // no Fri window
+		{"blackout", at(31, 23, 0), false, true},                  // Tue, but frozen
+	}
+	for _, tt := range tests {
+		state := evaluateChangeWindow(weekdayWindow(), tt.now)
+		if state.open != tt.wantOpen || state.frozen != tt.wantFrozen {
+			t.Errorf("%s: got open=%v frozen=%v (%s), want open=%v frozen=%v",
+				tt.name, state.open, state.frozen, state.reason, tt.wantOpen, tt.wantFrozen)
+		}
+	}
+
+	state := evaluateChangeWindow(weekdayWindow(), at(31, 23, 0))
+	if !strings.Contains(state.reason, "Q1 close") {
+		t.Errorf("expected blackout reason, got %q", state.reason)
+	}
+	state = evaluateChangeWindow(weekdayWindow(), at(20, 23, 0))
+	if !strings.Contains(state.reason, "next opens 2026-03-23T22:00:00Z") {
+		t.Errorf("expected next opening Monday 22:00, got %q", state.reason)
+	}
+}
+
+func TestEvaluateChangeWindow_InvalidFailsClosed(t *testing.T) {
+	cw := weekdayWindow()
+	cw.Spec.Timezone = "Mars/Olympus"
+	if state := evaluateChangeWindow(cw, time.Now()); state.open || !state.frozen {
+		t.Errorf("invalid timezone should freeze mutations, got %+v", state)
+	}
+}
+
+func changeWindowEngine(cw *corev1alpha1.ChangeWindow, now time.Time) *Engine {
+	eng := NewEngine("test-agent", &corev1alpha1.GuardrailsSpec{
+		Autonomy:      corev1alpha1.AutonomySafe,
+		MaxIterations: 10,
+	}, map[string]*skill.Action{
+		"restart-deploy": {ID: "restart-deploy", Tool: "kubectl.rollout", Tier: "service-mutation"},
+		"check-pods":     {ID: "check-pods", Tool: "kubectl.get", Tier: "read"},
+	}, nil).WithChangeWindow(cw)
+	eng.now = func() time.Time { return now }
+	return eng
+}
+
+func TestEngine_ChangeWindow(t *testing.T) {
+	// Wednesday 14:00 UTC — outside the window
+	closed := time.Date(2026, 3, 18, 14, 0, 0, 0, time.UTC)
+
+	eng := changeWindowEngine(weekdayWindow(), closed)
+	if d := eng.Evaluate("kubectl.get", "pods -n backstage"); !d.Allowed {
+		t.Errorf("read action should run outside the window, got blocked: %s", d.BlockReason)
+	}
+	d := eng.Evaluate("kubectl.rollout", "deployment backstage -n backstage")
+	if d.Allowed || d.NeedsApproval {
+		t.Fatal("mutation outside the window should be blocked")
+	}
+	if d.PreFlight.ChangeWindowCheck != "BLOCKED (outside change window)" {
+		t.Errorf("unexpected changeWindowCheck %q", d.PreFlight.ChangeWindowCheck)
+	}
+
+	// Escalate — approval requested instead
+	cw := weekdayWindow()
+	cw.Spec.OutsideWindow = corev1alpha1.OutsideWindowEscalate
+	d = changeWindowEngine(cw, closed).Evaluate("kubectl.rollout", "deployment backstage -n backstage")
+	if d.Allowed || !d.NeedsApproval || d.Status != corev1alpha1.ActionStatusPendingApproval {
+		t.Errorf("expected escalation for approval, got %+v", d)
+	}
+
+	// Freezes block even when escalating
+	frozen := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	d = changeWindowEngine(cw, frozen).Evaluate("kubectl.rollout", "deployment backstage -n backstage")
+	if d.Allowed || d.NeedsApproval || d.PreFlight.ChangeWindowCheck != "BLOCKED (change freeze)" {
+		t.Errorf("expected change freeze block, got %+v", d)
+	}
+
+	// Inside the window — allowed
+	open := time.Date(2026, 3, 17, 22, 30, 0, 0, time.UTC)
+	d = changeWindowEngine(weekdayWindow(), open).Evaluate("kubectl.rollout", "deployment backstage -n backstage")
+	if !d.Allowed || d.PreFlight.ChangeWindowCheck != "pass" {
+		t.Errorf("mutation inside the window should be allowed, got %+v", d)
+	}
+}
+
+func TestEngine_UnresolvedChangeWindow(t *testing.T) {
+	eng := changeWindowEngine(nil, time.Now()).
+		WithUnresolvedChangeWindow(errors.New(`change window "weekdays" resolution failed: not found`))
+
+	if d := eng.Evaluate("kubectl.get", "pods -n backstage"); !d.Allowed {
+		t.Errorf("read action should run without the window, got blocked: %s", d.BlockReason)
+	}
+	d := eng.Evaluate("kubectl.rollout", "deployment backstage -n backstage")
+	if d.Allowed || d.NeedsApproval || d.PreFlight.ChangeWindowCheck != "BLOCKED (change window unresolvable)" {
+		t.Errorf("mutation should be blocked without the window, got %+v", d)
+	}
+}
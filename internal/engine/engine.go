@@ -20,6 +20,7 @@ You may obtain a copy of the License at
 //  6. Check data resource impact
 //  7. Run pre-conditions
 //  8. Check cooldown
+//  9. Check change window (non-read actions)
 //
 // If any check fails, the action is BLOCKED. The LLM never sees the tool response.
 package engine
@@ -70,7 +71,17 @@ type Engine struct {
 	cooldowns        *CooldownTracker
 	protectionEngine *tools.ProtectionEngine
 	toolRegistry     *tools.Registry
+	changeWindow     *corev1alpha1.ChangeWindow
 	agentName        string
+
+	// changeWindowErr is why a referenced ChangeWindow couldn't be resolved.
+	changeWindowErr string
+
+	// autonomyCap lowers the configured autonomy for the rest of the run.
+	autonomyCap corev1alpha1.AutonomyLevel
+
+	// now is the clock for change window evaluation.
+	now func() time.Time
 }
 
 // NewEngine creates an engine for a specific agent run.
@@ -86,6 +97,7 @@ func NewEngine(
 		actionRegistry: actionRegistry,
 		dataIndex:      dataIndex,
 		cooldowns:      NewCooldownTracker(),
+		now:            time.Now,
 	}
 }
 
@@ -101,6 +113,39 @@ func (e *Engine) WithToolRegistry(reg *tools.Registry) *Engine {
 	return e
 }
 
+// WithChangeWindow restricts non-read actions to the ChangeWindow's open windows.
+func (e *Engine) WithChangeWindow(cw *corev1alpha1.ChangeWindow) *Engine {
+	e.changeWindow = cw
+	return e
+}
+
+// WithUnresolvedChangeWindow blocks non-read actions because the agent's
+// ChangeWindow couldn't be resolved: with no calendar to check, mutations
+// fail closed while reads carry on.
+func (e *Engine) WithUnresolvedChangeWindow(err error) *Engine {
+	e.changeWindowErr = err.Error()
+	return e
+}
+
+// LowerAutonomy caps the autonomy for the engine's remaining decisions. It
+// never raises it, and reports whether the effective level changed.
+func (e *Engine) LowerAutonomy(level corev1alpha1.AutonomyLevel) bool {
+	if level == "" || autonomyRank(level) >= autonomyRank(e.Autonomy()) {
+		return false
+	}
+	e.autonomyCap = level
+	return true
+}
+
+// Autonomy is the effective autonomy level: the configured level, unless
+// LowerAutonomy has capped it.
+func (e *Engine) Autonomy() corev1alpha1.AutonomyLevel {
+	if e.autonomyCap != "" {
+		return e.autonomyCap
+	}
+	return e.guardrails.Autonomy
+}
+
 // Evaluate runs all pre-flight checks for a tool call.
 // This is the single entry point — all safety enforcement happens here.
 func (e *Engine) Evaluate(toolName string, target string) *Decision {
@@ -183,8 +228,44 @@ func (e *Engine) Evaluate(toolName string, target string) *Decision {
 	}
 	d.PreFlight.DataImpactCheck = "pass"
 
+	// Step 4b: Check change window. Freezes and Block windows block here;
+	// Escalate windows request approval once every other check has passed.
+	escalateWindow := ""
+	if e.changeWindowErr != "" && d.Tier != corev1alpha1.ActionTierRead {
+		d.Allowed = false
+		d.Status = corev1alpha1.ActionStatusBlocked
+		d.PreFlight.ChangeWindowCheck = "BLOCKED (change window unresolvable)"
+		d.PreFlight.Reason = e.changeWindowErr
+		d.BlockReason = e.changeWindowErr
+		return d
+	}
+	if e.changeWindow != nil && d.Tier != corev1alpha1.ActionTierRead {
+		state := evaluateChangeWindow(e.changeWindow, e.now())
+		switch {
+		case state.open:
+			d.PreFlight.ChangeWindowCheck = "pass"
+		case state.frozen:
+			d.Allowed = false
+			d.Status = corev1alpha1.ActionStatusBlocked
+			d.PreFlight.ChangeWindowCheck = "BLOCKED (change freeze)"
+			d.PreFlight.Reason = state.reason
+			d.BlockReason = state.reason
+			return d
+		case e.changeWindow.Spec.OutsideWindow == corev1alpha1.OutsideWindowEscalate:
+			d.PreFlight.ChangeWindowCheck = "NEEDS_APPROVAL (outside change window)"
+			escalateWindow = state.reason
+		default:
+			d.Allowed = false
+			d.Status = corev1alpha1.ActionStatusBlocked
+			d.PreFlight.ChangeWindowCheck = "BLOCKED (outside change window)"
+			d.PreFlight.Reason = state.reason
+			d.BlockReason = state.reason
+			return d
+		}
+	}
+
 	// Step 5: Check autonomy level
-	if blocked, reason := checkAutonomy(d.Tier, e.guardrails.Autonomy); blocked {
+	if blocked, reason := checkAutonomy(d.Tier, e.Autonomy()); blocked {
 		// If approval mode is configured, request approval instead of hard block
 		if e.guardrails.ApprovalMode != "" && e.guardrails.ApprovalMode != "none" {
 			d.Allowed = false
@@ -246,6 +327,16 @@ func (e *Engine) Evaluate(toolName string, target string) *Decision {
 		return d
 	}
 
+	// Step 10: Escalate mutations outside the change window
+	if escalateWindow != "" {
+		d.Allowed = false
+		d.NeedsApproval = true
+		d.Status = corev1alpha1.ActionStatusPendingApproval
+		d.PreFlight.Reason = escalateWindow
+		d.BlockReason = escalateWindow
+		return d
+	}
+
 	return d
 }
 
@@ -387,6 +478,15 @@ func autonomyRank(level corev1alpha1.AutonomyLevel) int {
 	}
 }
 
+// MinAutonomy returns the lower of two autonomy levels. An empty level is
+// ignored.
+func MinAutonomy(a, b corev1alpha1.AutonomyLevel) corev1alpha1.AutonomyLevel {
+	if a == "" || (b != "" && autonomyRank(b) < autonomyRank(a)) {
+		return b
+	}
+	return a
+}
+
 // requiredAutonomy returns the minimum autonomy level for an action tier.
 func requiredAutonomy(tier corev1alpha1.ActionTier) corev1alpha1.AutonomyLevel {
 	switch tier {
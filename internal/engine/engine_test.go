@@ -543,6 +543,31 @@ func TestEngine_DenyListOverridesAllowList(t *testing.T) {
 	}
 }
 
+func TestEngine_LowerAutonomy(t *testing.T) {
+	eng := NewEngine("test-agent", &corev1alpha1.GuardrailsSpec{
+		Autonomy:      corev1alpha1.AutonomySafe,
+		MaxIterations: 10,
+	}, map[string]*skill.Action{
+		"restart-deploy": {ID: "restart-deploy", Tool: "kubectl.rollout", Tier: "service-mutation"},
+	}, nil)
+
+	if d := eng.Evaluate("kubectl.rollout", "deployment api -n prod"); !d.Allowed {
+		t.Fatalf("mutation should be allowed at automate-safe: %s", d.BlockReason)
+	}
+	if eng.LowerAutonomy(corev1alpha1.AutonomyDestructive) {
+		t.Error("LowerAutonomy must never raise the autonomy")
+	}
+	if !eng.LowerAutonomy(corev1alpha1.AutonomyObserve) || eng.Autonomy() != corev1alpha1.AutonomyObserve {
+		t.Fatalf("expected the autonomy to be lowered to observe, got %s", eng.Autonomy())
+	}
+	if d := eng.Evaluate("kubectl.rollout", "deployment api -n prod"); d.Allowed || d.PreFlight.AutonomyCheck != "BLOCKED" {
+		t.Errorf("mutation should be blocked once autonomy is lowered, got %+v", d.PreFlight)
+	}
+	if d := eng.Evaluate("kubectl.get", "pods -n prod"); !d.Allowed {
+		t.Errorf("reads should still be allowed: %s", d.BlockReason)
+	}
+}
+
 // --- Boundary tests (Step 2.29) ---
 
 func TestAutonomyRank(t *testing.T) {
@@ -557,6 +582,22 @@ func TestAutonomyRank(t *testing.T) {
 	}
 }
 
+func TestMinAutonomy(t *testing.T) {
+	tests := []struct {
+		a, b, want corev1alpha1.AutonomyLevel
+	}{
+		{corev1alpha1.AutonomySafe, corev1alpha1.AutonomyObserve, corev1alpha1.AutonomyObserve},
+		{corev1alpha1.AutonomyRecommend, corev1alpha1.AutonomyDestructive, corev1alpha1.AutonomyRecommend},
+		{corev1alpha1.AutonomySafe, "", corev1alpha1.AutonomySafe},
+		{"", corev1alpha1.AutonomySafe, corev1alpha1.AutonomySafe},
+	}
+	for _, tt := range tests {
+		if got := MinAutonomy(tt.a, tt.b); got != tt.want {
+			t.Errorf("MinAutonomy(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 // --- Protection Engine Integration tests ---
 
 func TestEngine_ProtectionEngine_BlocksSSH(t *testing.T) {
@@ -0,0 +1,107 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// preConditionTimeout bounds each precondition's tool call.
+const preConditionTimeout = 30 * time.Second
+
+// CheckPreConditions runs the matched action's preconditions (step 7) for a
+// decision that Evaluate allowed or sent for approval. Preconditions call
+// tools, so they need the tool call's arguments and run separately from
+// Evaluate. Checks run in order and the first failure applies its
+// failAction: block, escalate (needs approval) or skip. A check that can't
+// be run counts as failed.
+func (e *Engine) CheckPreConditions(ctx context.Context, d *Decision, args map[string]interface{}) {
+	if d.MatchedAction == nil || len(d.MatchedAction.PreConditions) == 0 || (!d.Allowed && !d.NeedsApproval) {
+		return
+	}
+
+	for _, pc := range d.MatchedAction.PreConditions {
+		passed, detail := e.runCheck(ctx, pc.Check, args)
+		if passed {
+			continue
+		}
+		reason := fmt.Sprintf("precondition %q failed: %s", pc.Check, detail)
+		d.PreFlight.Reason = reason
+		d.BlockReason = reason
+
+		switch pc.FailAction {
+		case skill.FailActionEscalate:
+			d.Allowed = false
+			d.NeedsApproval = true
+			d.Status = corev1alpha1.ActionStatusPendingApproval
+			d.PreFlight.PreConditionCheck = "NEEDS_APPROVAL"
+		case skill.FailActionSkip:
+			d.Allowed = false
+			d.NeedsApproval = false
+			d.Status = corev1alpha1.ActionStatusSkipped
+			d.PreFlight.PreConditionCheck = "SKIPPED"
+		default:
+			d.Allowed = false
+			d.NeedsApproval = false
+			d.Status = corev1alpha1.ActionStatusBlocked
+			d.PreFlight.PreConditionCheck = "BLOCKED"
+		}
+		return
+	}
+	d.PreFlight.PreConditionCheck = "pass"
+}
+
+// runCheck runs one precondition check, reporting whether it passed and,
+// if not, why.
+func (e *Engine) runCheck(ctx context.Context, source string, args map[string]interface{}) (bool, string) {
+	check, err := skill.ParseCheck(source)
+	if err != nil {
+		return false, fmt.Sprintf("invalid check: %v", err)
+	}
+	if e.toolRegistry == nil {
+		return false, "no tools available to run the check"
+	}
+
+	toolName := check.Tool
+	if check.Action != "" {
+		action, ok := e.actionRegistry[check.Action]
+		switch {
+		case !ok:
+			return false, fmt.Sprintf("action %q is not declared", check.Action)
+		case classifyTier(action.Tier) != corev1alpha1.ActionTierRead:
+			return false, fmt.Sprintf("action %q is not a read action", check.Action)
+		case strings.Contains(action.Tool, "*"):
+			return false, fmt.Sprintf("action %q does not name a single tool", check.Action)
+		}
+		toolName = action.Tool
+	}
+
+	callArgs := skill.ExpandArgs(check.Args, args)
+	target := tools.ExtractTarget(toolName, callArgs)
+	if d := e.Evaluate(toolName, target); !d.Allowed || d.Tier != corev1alpha1.ActionTierRead {
+		return false, fmt.Sprintf("%s on %s is not an allowed read", toolName, target)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, preConditionTimeout)
+	defer cancel()
+	output, err := e.toolRegistry.Execute(ctx, toolName, callArgs)
+	if err != nil {
+		return false, fmt.Sprintf("%s on %s: %v", toolName, target, err)
+	}
+	return check.Assert(output, args)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// podTool answers kubectl.get with a fixed pod status and records the
+// names it was asked about.
+type podTool struct {
+	status string
+	asked  []string
+}
+
+func (t *podTool) Name() string                       { return "kubectl.get" }
+func (t *podTool) Description() string                { return "get" }
+func (t *podTool) Parameters() map[string]interface{} { return nil }
+
+func (t *podTool) Execute(_ context.Context, args map[string]interface{}) (string, error) {
+	name, _ := args["name"].(string)
+	t.asked = append(t.asked, name)
+	return name + " " + t.status, nil
+}
+
+func preConditionEngine(status string, failAction string, check string) (*Engine, *podTool) {
+	tool := &podTool{status: status}
+	reg := tools.NewRegistry()
+	reg.Register(tool)
+	actions := map[string]*skill.Action{
+		"pod-status": {ID: "pod-status", Tool: "kubectl.get", Tier: "read"},
+		"restart": {ID: "restart", Tool: "kubectl.rollout", Tier: "service-mutation",
+			PreConditions: []skill.PreCondition{{Check: check, FailAction: failAction}}},
+	}
+	eng := NewEngine("test-agent", &corev1alpha1.GuardrailsSpec{Autonomy: corev1alpha1.AutonomySafe}, actions, nil)
+	return eng.WithToolRegistry(reg), tool
+}
+
+func TestCheckPreConditions_Pass(t *testing.T) {
+	eng, tool := preConditionEngine("CrashLoopBackOff", "", `action:pod-status resource=pod name=${name} contains "CrashLoopBackOff"`)
+
+	d := eng.Evaluate("kubectl.rollout", "deployment api")
+	eng.CheckPreConditions(context.Background(), d, map[string]interface{}{"name": "api"})
+
+	if !d.Allowed || d.PreFlight.PreConditionCheck != "pass" {
+		t.Errorf("expected the restart to pass, got %+v", d)
+	}
+	if len(tool.asked) != 1 || tool.asked[0] != "api" {
+		t.Errorf("the check should expand ${name}, asked %q", tool.asked)
+	}
+}
+
+func TestCheckPreConditions_FailActions(t *testing.T) {
+	tests := []struct {
+		failAction    string
+		wantStatus    corev1alpha1.ActionStatus
+		wantApproval  bool
+		wantPreFlight string
+	}{
+		{"", corev1alpha1.ActionStatusBlocked, false, "BLOCKED"},
+		{skill.FailActionBlock, corev1alpha1.ActionStatusBlocked, false, "BLOCKED"},
+		{skill.FailActionEscalate, corev1alpha1.ActionStatusPendingApproval, true, "NEEDS_APPROVAL"},
+		{skill.FailActionSkip, corev1alpha1.ActionStatusSkipped, false, "SKIPPED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.failAction, func(t *testing.T) {
+			eng, _ := preConditionEngine("Running", tt.failAction, `kubectl.get resource=pod name=${name} contains "CrashLoopBackOff"`)
+			d := eng.Evaluate("kubectl.rollout", "deployment api")
+			eng.CheckPreConditions(context.Background(), d, map[string]interface{}{"name": "api"})
+
+			if d.Allowed || d.Status != tt.wantStatus || d.NeedsApproval != tt.wantApproval || d.PreFlight.PreConditionCheck != tt.wantPreFlight {
+				t.Errorf("unexpected decision %+v", d)
+			}
+			if !strings.Contains(d.BlockReason, `does not contain "CrashLoopBackOff"`) {
+				t.Errorf("unexpected reason %q", d.BlockReason)
+			}
+		})
+	}
+}
+
+func TestCheckPreConditions_FailsClosed(t *testing.T) {
+	tests := map[string]string{
+		"unparseable":       "pods are crashlooping",
+		"unknown action":    "action:nope name=x",
+		"mutating action":   "action:restart name=x",
+		"mutating tool":     "kubectl.delete resource=pod name=x",
+		"unregistered tool": "http.get url=http://example.com",
+	}
+	for name, check := range tests {
+		t.Run(name, func(t *testing.T) {
+			eng, _ := preConditionEngine("Running", "", check)
+			d := eng.Evaluate("kubectl.rollout", "deployment api")
+			eng.CheckPreConditions(context.Background(), d, nil)
+			if d.Allowed || d.Status != corev1alpha1.ActionStatusBlocked {
+				t.Errorf("expected a block, got %+v", d)
+			}
+		})
+	}
+}
+
+func TestCheckPreConditions_SkipsBlockedDecisions(t *testing.T) {
+	eng, tool := preConditionEngine("Running", "", `kubectl.get name=${name} contains "x"`)
+	eng.guardrails.DeniedActions = []string{"kubectl.rollout*"}
+
+	d := eng.Evaluate("kubectl.rollout", "deployment api")
+	eng.CheckPreConditions(context.Background(), d, nil)
+	if len(tool.asked) != 0 || d.PreFlight.PreConditionCheck != "" {
+		t.Errorf("preconditions should not run for a blocked action, asked %q", tool.asked)
+	}
+}
@@ -100,6 +100,69 @@ var (
 		[]string{"agent"},
 	)
 
+	// WebhookAuthFailuresTotal counts inbound webhook requests rejected by authentication.
+	WebhookAuthFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "legator_webhook_auth_failures_total",
+			Help: "Total inbound webhook requests rejected with 401, by source and reason.",
+		},
+		[]string{"source", "reason"},
+	)
+
+	// TriggerQueueDepth is the number of queued RunRequests by phase.
+	TriggerQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "legator_trigger_queue_depth",
+			Help: "Queued triggers (RunRequests) awaiting dispatch, by phase.",
+		},
+		[]string{"phase"},
+	)
+
+	// CompactionsTotal counts conversation compactions by agent and strategy.
+	CompactionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "legator_conversation_compactions_total",
+			Help: "Total conversation compactions performed to stay within token budgets.",
+		},
+		[]string{"agent", "strategy"},
+	)
+
+	// ModelFallbacksTotal counts LLM calls that failed over to a fallback model.
+	ModelFallbacksTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "legator_model_fallbacks_total",
+			Help: "Total LLM calls that failed over from one model to the next in its tier's fallback chain.",
+		},
+		[]string{"agent", "from", "to"},
+	)
+
+	// CostUSDTotal is the estimated spend on LLM calls by agent and model.
+	CostUSDTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "legator_cost_usd_total",
+			Help: "Total estimated USD cost of LLM calls, priced from the ModelTierConfig.",
+		},
+		[]string{"agent", "model"},
+	)
+
+	// RedactionsTotal counts secrets redacted from content sent to LLM providers.
+	RedactionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "legator_redactions_total",
+			Help: "Total secrets redacted from prompts and tool output before they were sent to an LLM provider.",
+		},
+		[]string{"agent"},
+	)
+
+	// PromptInjectionsTotal counts tool outputs that looked like prompt injections.
+	PromptInjectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "legator_prompt_injections_total",
+			Help: "Total tool outputs matching prompt-injection patterns, by agent and pattern.",
+		},
+		[]string{"agent", "pattern"},
+	)
+
 	// ActiveRuns is the number of currently executing agent runs.
 	ActiveRuns = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -119,6 +182,13 @@ func init() {
 		FindingsTotal,
 		EscalationsTotal,
 		ScheduleLagSeconds,
+		WebhookAuthFailuresTotal,
+		TriggerQueueDepth,
+		CompactionsTotal,
+		ModelFallbacksTotal,
+		CostUSDTotal,
+		RedactionsTotal,
+		PromptInjectionsTotal,
 		ActiveRuns,
 	)
 }
@@ -150,3 +220,39 @@ func RecordEscalation(agent, reason string) {
 func RecordScheduleLag(agent string, lag time.Duration) {
 	ScheduleLagSeconds.WithLabelValues(agent).Set(lag.Seconds())
 }
+
+// RecordWebhookAuthFailure records a webhook request rejected by authentication.
+func RecordWebhookAuthFailure(source, reason string) {
+	WebhookAuthFailuresTotal.WithLabelValues(source, reason).Inc()
+}
+
+// RecordTriggerQueueDepth sets the trigger queue depth gauges.
+func RecordTriggerQueueDepth(pending, failed int) {
+	TriggerQueueDepth.WithLabelValues("Pending").Set(float64(pending))
+	TriggerQueueDepth.WithLabelValues("Failed").Set(float64(failed))
+}
+
+// RecordCompaction records a single conversation compaction.
+func RecordCompaction(agent, strategy string) {
+	CompactionsTotal.WithLabelValues(agent, strategy).Inc()
+}
+
+// RecordModelFallback records a failover from one model to another.
+func RecordModelFallback(agent, from, to string) {
+	ModelFallbacksTotal.WithLabelValues(agent, from, to).Inc()
+}
+
+// RecordCost records the estimated cost of a run's calls to one model.
+func RecordCost(agent, model string, usd float64) {
+	CostUSDTotal.WithLabelValues(agent, model).Add(usd)
+}
+
+// RecordRedactions records the secrets redacted during a run.
+func RecordRedactions(agent string, n int32) {
+	RedactionsTotal.WithLabelValues(agent).Add(float64(n))
+}
+
+// RecordPromptInjection records tool output matching an injection pattern.
+func RecordPromptInjection(agent, pattern string) {
+	PromptInjectionsTotal.WithLabelValues(agent, pattern).Inc()
+}
@@ -112,6 +112,15 @@ func TestRecordEscalation(t *testing.T) {
 	}
 }
 
+func TestRecordWebhookAuthFailure(t *testing.T) {
+	RecordWebhookAuthFailure("github", "invalid-signature")
+
+	val := getCounterValue(WebhookAuthFailuresTotal, "github", "invalid-signature")
+	if val < 1 {
+		t.Errorf("WebhookAuthFailuresTotal = %f, want >= 1", val)
+	}
+}
+
 func TestRecordScheduleLag(t *testing.T) {
 	RecordScheduleLag("watchman-light", 12*time.Second)
 
@@ -128,6 +137,62 @@ func TestRecordScheduleLag(t *testing.T) {
 	}
 }
 
+func TestRecordTriggerQueueDepth(t *testing.T) {
+	RecordTriggerQueueDepth(7, 1)
+
+	if val := getGaugeVecValue(TriggerQueueDepth, "Pending"); val != 7 {
+		t.Errorf("TriggerQueueDepth{Pending} = %f, want 7", val)
+	}
+	if val := getGaugeVecValue(TriggerQueueDepth, "Failed"); val != 1 {
+		t.Errorf("TriggerQueueDepth{Failed} = %f, want 1", val)
+	}
+}
+
+func TestRecordCompaction(t *testing.T) {
+	RecordCompaction("forge", "Summarize")
+
+	val := getCounterValue(CompactionsTotal, "forge", "Summarize")
+	if val < 1 {
+		t.Errorf("CompactionsTotal = %f, want >= 1", val)
+	}
+}
+
+func TestRecordModelFallback(t *testing.T) {
+	RecordModelFallback("forge", "anthropic/claude-opus-4", "openai/gpt-4o")
+
+	val := getCounterValue(ModelFallbacksTotal, "forge", "anthropic/claude-opus-4", "openai/gpt-4o")
+	if val < 1 {
+		t.Errorf("ModelFallbacksTotal = %f, want >= 1", val)
+	}
+}
+
+func TestRecordCost(t *testing.T) {
+	RecordCost("forge", "anthropic/claude-sonnet-4", 0.25)
+
+	val := getCounterValue(CostUSDTotal, "forge", "anthropic/claude-sonnet-4")
+	if val < 0.25 {
+		t.Errorf("CostUSDTotal = %f, want >= 0.25", val)
+	}
+}
+
+func TestRecordRedactions(t *testing.T) {
+	RecordRedactions("forge", 3)
+
+	val := getCounterValue(RedactionsTotal, "forge")
+	if val < 3 {
+		t.Errorf("RedactionsTotal = %f, want >= 3", val)
+	}
+}
+
+func TestRecordPromptInjection(t *testing.T) {
+	RecordPromptInjection("forge", "instruction-override")
+
+	val := getCounterValue(PromptInjectionsTotal, "forge", "instruction-override")
+	if val < 1 {
+		t.Errorf("PromptInjectionsTotal = %f, want >= 1", val)
+	}
+}
+
 func TestActiveRuns(t *testing.T) {
 	ActiveRuns.Set(0) // Reset
 
@@ -324,12 +324,11 @@ func (p *AnthropicProvider) doWithRetry(ctx context.Context, body []byte, result
 			if attempt < p.maxRetries {
 				continue
 			}
-			return fmt.Errorf("anthropic API returned %d after %d retries: %s",
-				httpResp.StatusCode, p.maxRetries, string(respBody))
+			return &APIError{Provider: "anthropic", StatusCode: httpResp.StatusCode, Retries: p.maxRetries, Body: string(respBody)}
 		}
 
 		if httpResp.StatusCode != 200 {
-			return fmt.Errorf("anthropic API returned %d: %s", httpResp.StatusCode, string(respBody))
+			return &APIError{Provider: "anthropic", StatusCode: httpResp.StatusCode, Body: string(respBody)}
 		}
 
 		if err := json.Unmarshal(respBody, result); err != nil {
@@ -0,0 +1,237 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package provider
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Cassette modes.
+const (
+	// CassetteRecord passes requests to the upstream provider and appends
+	// every request/response pair to the cassette file.
+	CassetteRecord = "record"
+
+	// CassetteReplay serves responses from the cassette file without
+	// calling any model.
+	CassetteReplay = "replay"
+)
+
+// cassetteWriteMu serializes appends, since every run gets its own
+// recorder but they may share a file.
+var cassetteWriteMu sync.Mutex
+
+// CassetteInteraction is one recorded request/response pair. A cassette
+// file holds one interaction per line, as JSON.
+type CassetteInteraction struct {
+	// Hash is the normalized request hash (see RequestHash).
+	Hash string `json:"hash"`
+
+	Request  *CompletionRequest  `json:"request"`
+	Response *CompletionResponse `json:"response,omitempty"`
+
+	// Error is the upstream error, if the call failed.
+	Error string `json:"error,omitempty"`
+}
+
+// CassetteProvider records model interactions to a file, or replays them.
+// In replay mode, requests are matched by RequestHash; identical requests
+// are answered in the order they were recorded, repeating the last answer
+// once they run out.
+type CassetteProvider struct {
+	mode     string
+	path     string
+	upstream Provider
+
+	mu      sync.Mutex
+	replies map[string][]CassetteInteraction
+	served  map[string]int
+}
+
+// NewCassetteProvider creates a cassette provider from config. In record
+// mode the upstream provider is built from CassetteUpstream.
+func NewCassetteProvider(cfg ProviderConfig) (*CassetteProvider, error) {
+	if cfg.CassettePath == "" {
+		return nil, fmt.Errorf("cassette provider requires a cassette path")
+	}
+	switch cfg.CassetteMode {
+	case CassetteRecord:
+		upstreamCfg := cfg
+		upstreamCfg.Type = cfg.CassetteUpstream
+		upstream, err := NewProvider(upstreamCfg)
+		if err != nil {
+			return nil, fmt.Errorf("cassette upstream: %w", err)
+		}
+		return NewCassetteRecorder(upstream, cfg.CassettePath), nil
+	case CassetteReplay, "":
+		return NewCassettePlayer(cfg.CassettePath)
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode: %q", cfg.CassetteMode)
+	}
+}
+
+// NewCassetteRecorder wraps upstream, appending each interaction to path.
+func NewCassetteRecorder(upstream Provider, path string) *CassetteProvider {
+	return &CassetteProvider{mode: CassetteRecord, path: path, upstream: upstream}
+}
+
+// NewCassettePlayer loads a cassette file for replay.
+func NewCassettePlayer(path string) (*CassetteProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open cassette: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	p := &CassetteProvider{
+		mode:    CassetteReplay,
+		path:    path,
+		replies: make(map[string][]CassetteInteraction),
+		served:  make(map[string]int),
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var in CassetteInteraction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("cassette %s line %d: %w", path, line, err)
+		}
+		p.replies[in.Hash] = append(p.replies[in.Hash], in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	return p, nil
+}
+
+// Complete records or replays a completion.
+func (p *CassetteProvider) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	hash := RequestHash(req)
+	if p.mode == CassetteReplay {
+		return p.replay(hash)
+	}
+
+	resp, err := p.upstream.Complete(ctx, req)
+	in := CassetteInteraction{Hash: hash, Request: req, Response: resp}
+	if err != nil {
+		in.Response = nil
+		in.Error = err.Error()
+	}
+	if werr := p.append(in); werr != nil {
+		return nil, werr
+	}
+	return resp, err
+}
+
+// Name returns the provider identifier.
+func (p *CassetteProvider) Name() string {
+	return "cassette"
+}
+
+func (p *CassetteProvider) replay(hash string) (*CompletionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	replies := p.replies[hash]
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("cassette %s has no recorded response for request %s", p.path, hash)
+	}
+	i := min(p.served[hash], len(replies)-1)
+	p.served[hash]++
+
+	in := replies[i]
+	if in.Error != "" {
+		return nil, errors.New(in.Error)
+	}
+	return in.Response, nil
+}
+
+func (p *CassetteProvider) append(in CassetteInteraction) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encode cassette interaction: %w", err)
+	}
+
+	cassetteWriteMu.Lock()
+	defer cassetteWriteMu.Unlock()
+
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open cassette: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return f.Close()
+}
+
+// RequestHash returns a hash of the parts of a request that determine the
+// model's answer: the model, system prompt, conversation and tool names.
+// Tool call IDs (assigned afresh by the model on every run), tool
+// descriptions and MaxTokens are left out so that recordings survive
+// cosmetic changes.
+func RequestHash(req *CompletionRequest) string {
+	type call struct {
+		Name string                 `json:"n"`
+		Args map[string]interface{} `json:"a,omitempty"`
+	}
+	type result struct {
+		Content string `json:"c"`
+		IsError bool   `json:"e,omitempty"`
+	}
+	type message struct {
+		Role        string   `json:"r"`
+		Content     string   `json:"c,omitempty"`
+		ToolCalls   []call   `json:"t,omitempty"`
+		ToolResults []result `json:"tr,omitempty"`
+	}
+	normalized := struct {
+		Model        string    `json:"model"`
+		SystemPrompt string    `json:"system"`
+		Messages     []message `json:"messages"`
+		Tools        []string  `json:"tools,omitempty"`
+	}{
+		Model:        req.Model,
+		SystemPrompt: req.SystemPrompt,
+	}
+	for _, m := range req.Messages {
+		nm := message{Role: m.Role, Content: m.Content}
+		for _, tc := range m.ToolCalls {
+			nm.ToolCalls = append(nm.ToolCalls, call{Name: tc.Name, Args: tc.Args})
+		}
+		for _, tr := range m.ToolResults {
+			nm.ToolResults = append(nm.ToolResults, result{Content: tr.Content, IsError: tr.IsError})
+		}
+		normalized.Messages = append(normalized.Messages, nm)
+	}
+	for _, t := range req.Tools {
+		normalized.Tools = append(normalized.Tools, t.Name)
+	}
+	sort.Strings(normalized.Tools)
+
+	// Marshal sorts map keys, so args hash the same regardless of order
+	data, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
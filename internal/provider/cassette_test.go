@@ -0,0 +1,119 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func cassetteRequest(callID string, maxTokens int32) *CompletionRequest {
+	return &CompletionRequest{
+		SystemPrompt: "You are a watchman.",
+		Model:        "claude-sonnet-4-20250514",
+		MaxTokens:    maxTokens,
+		Tools:        []ToolDefinition{{Name: "kubectl.get"}, {Name: "kubectl.describe"}},
+		Messages: []Message{
+			{Role: "user", Content: "Execute your task now."},
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: callID, Name: "kubectl.get", Args: map[string]interface{}{"resource": "pods", "namespace": "default"}}}},
+			{Role: "user", ToolResults: []ToolResult{{ToolCallID: callID, Content: "api-0 Running"}}},
+		},
+	}
+}
+
+func TestRequestHash_Normalizes(t *testing.T) {
+	base := RequestHash(cassetteRequest("toolu_1", 4096))
+
+	if got := RequestHash(cassetteRequest("toolu_2", 1024)); got != base {
+		t.Error("hash should ignore tool call IDs and MaxTokens")
+	}
+
+	reordered := cassetteRequest("toolu_1", 4096)
+	reordered.Tools[0], reordered.Tools[1] = reordered.Tools[1], reordered.Tools[0]
+	if RequestHash(reordered) != base {
+		t.Error("hash should ignore tool definition order")
+	}
+
+	changed := cassetteRequest("toolu_1", 4096)
+	changed.Messages[2].ToolResults[0].Content = "api-0 CrashLoopBackOff"
+	if RequestHash(changed) == base {
+		t.Error("hash should change when a tool result changes")
+	}
+}
+
+func TestCassette_RecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchman.cassette")
+	ctx := context.Background()
+
+	upstream := NewMockProvider([]*CompletionResponse{
+		{Content: "first", Usage: UsageInfo{InputTokens: 10, OutputTokens: 5}},
+		{Content: "second"},
+		nil,
+	}, []error{nil, nil, errors.New("rate limited")})
+	rec := NewCassetteRecorder(upstream, path)
+
+	req := cassetteRequest("toolu_1", 4096)
+	other := cassetteRequest("toolu_1", 4096)
+	other.SystemPrompt = "You are a different agent."
+	for _, r := range []*CompletionRequest{req, req, other} {
+		_, _ = rec.Complete(ctx, r)
+	}
+
+	player, err := NewCassettePlayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Identical requests are answered in recorded order, then the last repeats
+	for _, want := range []string{"first", "second", "second"} {
+		resp, err := player.Complete(ctx, cassetteRequest("toolu_9", 100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Content != want {
+			t.Errorf("got %q, want %q", resp.Content, want)
+		}
+	}
+
+	if _, err := player.Complete(ctx, other); err == nil || err.Error() != "rate limited" {
+		t.Errorf("expected the recorded error, got %v", err)
+	}
+
+	unknown := cassetteRequest("toolu_1", 4096)
+	unknown.Model = "gpt-4o"
+	if _, err := player.Complete(ctx, unknown); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("expected a miss, got %v", err)
+	}
+	if upstream.CallCount() != 3 {
+		t.Errorf("replay should not call upstream, got %d calls", upstream.CallCount())
+	}
+}
+
+func TestNewProvider_Cassette(t *testing.T) {
+	if _, err := NewProvider(ProviderConfig{Type: "cassette"}); err == nil {
+		t.Error("expected an error without a cassette path")
+	}
+	if _, err := NewProvider(ProviderConfig{Type: "cassette", CassettePath: "/nonexistent/cassette"}); err == nil {
+		t.Error("expected an error for a missing cassette file")
+	}
+	_, err := NewProvider(ProviderConfig{
+		Type:             "cassette",
+		CassettePath:     filepath.Join(t.TempDir(), "new.cassette"),
+		CassetteMode:     CassetteRecord,
+		CassetteUpstream: "anthropic",
+	})
+	if err == nil || !strings.Contains(err.Error(), "API key") {
+		t.Errorf("expected the upstream's config error, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the model while a provider's
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Circuit breaker defaults: a circuit opens after this many consecutive
+// failed calls and stays open for the cooldown.
+const (
+	DefaultCircuitThreshold = 3
+	DefaultCircuitCooldown  = time.Minute
+)
+
+// CircuitBreaker stops calls to a model endpoint that keeps failing, so
+// runs fail over straight away instead of waiting out its timeouts and
+// retries. After the cooldown one trial call is let through; it closes the
+// circuit if it succeeds and re-opens it if not.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultCircuitThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitCooldown
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow returns ErrCircuitOpen while the circuit is open.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	if b.failures >= b.threshold {
+		// Half-open: hold the circuit open for other callers while this
+		// trial call runs
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+	return nil
+}
+
+// Record records a call's outcome. Cancellation by the caller says nothing
+// about the endpoint and is ignored.
+func (b *CircuitBreaker) Record(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// breakers holds one circuit breaker per model endpoint, shared by all runs.
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*CircuitBreaker{}
+)
+
+// Breaker returns the shared circuit breaker for a key (typically
+// "provider/model@endpoint"), creating it with the defaults.
+func Breaker(key string) *CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[key]
+	if !ok {
+		b = NewCircuitBreaker(DefaultCircuitThreshold, DefaultCircuitCooldown)
+		breakers[key] = b
+	}
+	return b
+}
+
+// circuitProvider guards a provider with a circuit breaker.
+type circuitProvider struct {
+	Provider
+	breaker *CircuitBreaker
+}
+
+// WithCircuitBreaker wraps a provider so calls fail fast with
+// ErrCircuitOpen while the breaker is open.
+func WithCircuitBreaker(p Provider, b *CircuitBreaker) Provider {
+	return &circuitProvider{Provider: p, breaker: b}
+}
+
+func (p *circuitProvider) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	if err := p.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Name(), err)
+	}
+	resp, err := p.Provider.Complete(ctx, req)
+	p.breaker.Record(err)
+	return resp, err
+}
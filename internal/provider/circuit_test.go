@@ -0,0 +1,74 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+	fail := errors.New("503 service unavailable")
+
+	b.Record(fail)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("circuit opened after 1 failure: %v", err)
+	}
+	b.Record(fail)
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to open after 2 failures, got %v", err)
+	}
+
+	// After the cooldown one trial call is allowed; others still fail fast
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected a trial call after the cooldown, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to stay open during the trial call, got %v", err)
+	}
+
+	b.Record(nil)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected a successful trial to close the circuit, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_IgnoresCancellation(t *testing.T) {
+	b := NewCircuitBreaker(1, time.Minute)
+	b.Record(context.Canceled)
+	if err := b.Allow(); err != nil {
+		t.Errorf("cancellation should not open the circuit: %v", err)
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	mock := NewMockProvider(
+		[]*CompletionResponse{nil, nil},
+		[]error{errors.New("connection refused"), errors.New("connection refused")},
+	)
+	p := WithCircuitBreaker(mock, NewCircuitBreaker(1, time.Minute))
+
+	if _, err := p.Complete(context.Background(), &CompletionRequest{}); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the provider's error, got %v", err)
+	}
+	if _, err := p.Complete(context.Background(), &CompletionRequest{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if len(mock.Calls()) != 1 {
+		t.Errorf("expected the open circuit to skip the model, got %d calls", len(mock.Calls()))
+	}
+}
@@ -295,12 +295,11 @@ func (p *OpenAIProvider) doWithRetry(ctx context.Context, body []byte, result *o
 			if attempt < p.maxRetries {
 				continue
 			}
-			return fmt.Errorf("openai API returned %d after %d retries: %s",
-				httpResp.StatusCode, p.maxRetries, string(respBody))
+			return &APIError{Provider: "openai", StatusCode: httpResp.StatusCode, Retries: p.maxRetries, Body: string(respBody)}
 		}
 
 		if httpResp.StatusCode != 200 {
-			return fmt.Errorf("openai API returned %d: %s", httpResp.StatusCode, string(respBody))
+			return &APIError{Provider: "openai", StatusCode: httpResp.StatusCode, Body: string(respBody)}
 		}
 
 		if err := json.Unmarshal(respBody, result); err != nil {
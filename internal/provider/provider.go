@@ -15,7 +15,12 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 )
 
 // Provider is the interface for LLM backends.
@@ -133,9 +138,48 @@ func (u UsageInfo) TotalTokens() int64 {
 	return u.InputTokens + u.OutputTokens
 }
 
+// APIError is a non-200 response from a model API.
+type APIError struct {
+	// Provider is the provider that called the API.
+	Provider string
+
+	// StatusCode is the HTTP status of the last attempt.
+	StatusCode int
+
+	// Retries is how many times the request was retried.
+	Retries int
+
+	// Body is the response body.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	if e.Retries > 0 {
+		return fmt.Sprintf("%s API returned %d after %d retries: %s", e.Provider, e.StatusCode, e.Retries, e.Body)
+	}
+	return fmt.Sprintf("%s API returned %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Unavailable reports whether err means the model couldn't answer rather
+// than that the request was wrong: a transport failure, a 429 or 5xx
+// response, or an open circuit. Another model may answer the same request;
+// a 400 or 401 would fail there too.
+func Unavailable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
 // ProviderConfig holds configuration for creating a provider.
 type ProviderConfig struct {
-	// Type is the provider type: "anthropic", "openai".
+	// Type is the provider type: "anthropic", "openai", "cassette".
 	Type string
 
 	// Endpoint is the API base URL (empty for default).
@@ -152,6 +196,16 @@ type ProviderConfig struct {
 
 	// TimeoutSeconds is the per-request timeout (default 120).
 	TimeoutSeconds int
+
+	// CassettePath is the cassette file for the "cassette" type.
+	CassettePath string
+
+	// CassetteMode is "record" or "replay" (default) for the "cassette" type.
+	CassetteMode string
+
+	// CassetteUpstream is the provider type that answers requests while
+	// recording a cassette (e.g. "anthropic").
+	CassetteUpstream string
 }
 
 // NewProvider creates a provider from config.
@@ -161,6 +215,8 @@ func NewProvider(cfg ProviderConfig) (Provider, error) {
 		return NewAnthropicProvider(cfg)
 	case "openai":
 		return NewOpenAIProvider(cfg)
+	case "cassette":
+		return NewCassetteProvider(cfg)
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %q", cfg.Type)
 	}
@@ -12,6 +12,9 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/url"
 	"testing"
 )
 
@@ -161,3 +164,23 @@ func TestMockProviderName(t *testing.T) {
 		t.Errorf("expected 'mock', got %q", mock.Name())
 	}
 }
+
+func TestUnavailable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&APIError{Provider: "anthropic", StatusCode: 503}, true},
+		{&APIError{Provider: "openai", StatusCode: 429, Retries: 3}, true},
+		{&APIError{Provider: "anthropic", StatusCode: 400}, false},
+		{&APIError{Provider: "openai", StatusCode: 401}, false},
+		{fmt.Errorf("HTTP request failed: %w", &url.Error{Op: "Post", URL: "https://api", Err: errors.New("connection refused")}), true},
+		{fmt.Errorf("anthropic: %w", ErrCircuitOpen), true},
+		{errors.New("build request: bad tool schema"), false},
+	}
+	for _, tt := range tests {
+		if got := Unavailable(tt.err); got != tt.want {
+			t.Errorf("Unavailable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -88,6 +88,14 @@ func NewLimiter(cfg Config) *Limiter {
 
 // Allow checks whether a new run for the given agent is permitted.
 func (l *Limiter) Allow(agentKey string, isWebhook bool) Decision {
+	return l.AllowConcurrent(agentKey, isWebhook, l.config.MaxConcurrentPerAgent)
+}
+
+// AllowConcurrent is Allow with the per-agent concurrency limit overridden,
+// for agents whose concurrency policy permits parallel runs. maxPerAgent of
+// zero or less skips the per-agent concurrency check (e.g. when the in-flight
+// run is being replaced).
+func (l *Limiter) AllowConcurrent(agentKey string, isWebhook bool, maxPerAgent int) Decision {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -95,10 +103,10 @@ func (l *Limiter) Allow(agentKey string, isWebhook bool) Decision {
 	l.pruneHistory(now)
 
 	// Per-agent concurrency
-	if l.concurrent[agentKey] >= l.config.MaxConcurrentPerAgent {
+	if maxPerAgent > 0 && l.concurrent[agentKey] >= maxPerAgent {
 		return Decision{
 			Allowed: false,
-			Reason:  fmt.Sprintf("per-agent concurrency limit reached (%d/%d)", l.concurrent[agentKey], l.config.MaxConcurrentPerAgent),
+			Reason:  fmt.Sprintf("per-agent concurrency limit reached (%d/%d)", l.concurrent[agentKey], maxPerAgent),
 		}
 	}
 
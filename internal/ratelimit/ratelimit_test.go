@@ -41,6 +41,25 @@ func TestAllow_PerAgentConcurrency(t *testing.T) {
 	}
 }
 
+func TestAllowConcurrent_OverridesPerAgentLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxConcurrentPerAgent = 1
+	l := NewLimiter(cfg)
+
+	l.RecordStart("ns/agent-a")
+	l.RecordStart("ns/agent-a")
+
+	if d := l.AllowConcurrent("ns/agent-a", false, 3); !d.Allowed {
+		t.Fatalf("expected allowed under limit 3: %s", d.Reason)
+	}
+	if d := l.AllowConcurrent("ns/agent-a", false, 2); d.Allowed {
+		t.Fatal("expected blocked at limit 2")
+	}
+	if d := l.AllowConcurrent("ns/agent-a", false, 0); !d.Allowed {
+		t.Fatalf("expected per-agent check skipped with limit 0: %s", d.Reason)
+	}
+}
+
 func TestAllow_ClusterWideConcurrency(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.MaxConcurrentCluster = 2
@@ -260,9 +260,12 @@ type WebhookPayload struct {
 }
 
 type WebhookFinding struct {
-	Severity string `json:"severity"`
-	Resource string `json:"resource,omitempty"`
-	Message  string `json:"message"`
+	ID          string `json:"id,omitempty"`
+	Severity    string `json:"severity"`
+	Resource    string `json:"resource,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
 }
 
 type WebhookUsage struct {
@@ -322,9 +325,12 @@ func buildWebhookPayload(report *Report) WebhookPayload {
 
 	for _, f := range report.Findings {
 		payload.Findings = append(payload.Findings, WebhookFinding{
-			Severity: string(f.Severity),
-			Resource: f.Resource,
-			Message:  f.Message,
+			ID:          f.ID,
+			Severity:    string(f.Severity),
+			Resource:    f.Resource,
+			Category:    f.Category,
+			Message:     f.Message,
+			Remediation: f.Remediation,
 		})
 	}
 
@@ -386,6 +392,9 @@ func formatFindings(findings []corev1alpha1.RunFinding) string {
 		} else {
 			fmt.Fprintf(&b, "%s %s\n", icon, f.Message)
 		}
+		if f.Remediation != "" {
+			fmt.Fprintf(&b, "    ↳ %s\n", f.Remediation)
+		}
 	}
 	return b.String()
 }
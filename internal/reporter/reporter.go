@@ -208,6 +208,9 @@ func FromLegatorRun(agent *corev1alpha1.LegatorAgent, run *corev1alpha1.LegatorR
 	case corev1alpha1.RunPhaseBlocked:
 		report.Severity = SeverityEscalation
 		report.Summary = "Run blocked — all actions denied"
+	case corev1alpha1.RunPhaseAborted:
+		report.Severity = SeverityWarning
+		report.Summary = fmt.Sprintf("Run aborted by %s", run.Status.AbortedBy)
 	default:
 		report.Severity = SeverityInfo
 		report.Summary = fmt.Sprintf("Run ended with phase: %s", run.Status.Phase)
@@ -260,15 +263,30 @@ func EstimateCost(usage *corev1alpha1.UsageSummary, inputCostPerMillion, outputC
 		return ""
 	}
 
-	cost := (float64(usage.TokensIn) * inputRate / 1_000_000) +
-		(float64(usage.TokensOut) * outputRate / 1_000_000)
+	return FormatCost(Cost(usage.TokensIn, usage.TokensOut, inputCostPerMillion, outputCostPerMillion))
+}
+
+// Cost calculates the USD cost of tokens at the given per-million pricing.
+// Unset or invalid prices count as zero.
+func Cost(tokensIn, tokensOut int64, inputCostPerMillion, outputCostPerMillion string) float64 {
+	return (float64(tokensIn) * parseFloat(inputCostPerMillion) / 1_000_000) +
+		(float64(tokensOut) * parseFloat(outputCostPerMillion) / 1_000_000)
+}
 
+// FormatCost formats a USD cost, with four decimal places below a cent.
+func FormatCost(cost float64) string {
 	if cost < 0.01 {
 		return fmt.Sprintf("$%.4f", cost)
 	}
 	return fmt.Sprintf("$%.2f", cost)
 }
 
+// ParseCost parses a USD amount, with or without a leading "$". Invalid
+// amounts parse as zero.
+func ParseCost(s string) float64 {
+	return parseFloat(strings.TrimPrefix(strings.TrimSpace(s), "$"))
+}
+
 func parseFloat(s string) float64 {
 	var f float64
 	fmt.Sscanf(s, "%f", &f)
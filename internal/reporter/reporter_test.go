@@ -420,6 +420,20 @@ func TestEstimateCost_NilUsage(t *testing.T) {
 	}
 }
 
+func TestFormatAndParseCost(t *testing.T) {
+	for _, cost := range []float64{0.0012, 0.5, 12.34} {
+		if got := ParseCost(FormatCost(cost)); got != cost {
+			t.Errorf("ParseCost(FormatCost(%v)) = %v", cost, got)
+		}
+	}
+	if got := ParseCost("2.50"); got != 2.5 {
+		t.Errorf("ParseCost without $ = %v, want 2.5", got)
+	}
+	if got := ParseCost("lots"); got != 0 {
+		t.Errorf("ParseCost of an invalid amount = %v, want 0", got)
+	}
+}
+
 // --- Formatting tests ---
 
 func TestSeverityIcon(t *testing.T) {
@@ -57,6 +57,13 @@ type ResolvedEnvironment struct {
 
 	// DataIndex is the pre-built index for fast data resource lookups.
 	DataIndex *DataResourceIndex
+
+	// ChangeWindowRef names the environment's ChangeWindow (if any).
+	ChangeWindowRef string
+
+	// RedactionPatterns are extra patterns redacted from content sent to
+	// the LLM provider.
+	RedactionPatterns []string
 }
 
 // DataResourceIndex provides O(1) lookups for data resource membership.
@@ -109,15 +116,19 @@ func (r *EnvironmentResolver) Resolve(ctx context.Context, envName string) (*Res
 	}
 
 	resolved := &ResolvedEnvironment{
-		Name:           env.Name,
-		Endpoints:      env.Spec.Endpoints,
-		Namespaces:     env.Spec.Namespaces,
-		Channels:       env.Spec.Channels,
-		DataResources:  env.Spec.DataResources,
-		MCPServers:     env.Spec.MCPServers,
-		Connectivity:   env.Spec.Connectivity,
-		VaultConfig:    env.Spec.Vault,
-		RawCredentials: env.Spec.Credentials,
+		Name:            env.Name,
+		Endpoints:       env.Spec.Endpoints,
+		Namespaces:      env.Spec.Namespaces,
+		Channels:        env.Spec.Channels,
+		DataResources:   env.Spec.DataResources,
+		MCPServers:      env.Spec.MCPServers,
+		Connectivity:    env.Spec.Connectivity,
+		VaultConfig:     env.Spec.Vault,
+		RawCredentials:  env.Spec.Credentials,
+		ChangeWindowRef: env.Spec.ChangeWindowRef,
+	}
+	if env.Spec.Redaction != nil {
+		resolved.RedactionPatterns = env.Spec.Redaction.Patterns
 	}
 
 	// Resolve credentials from Kubernetes Secrets
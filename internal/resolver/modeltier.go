@@ -115,3 +115,54 @@ func ResolveTierFromConfig(config *corev1alpha1.ModelTierConfig, tier corev1alph
 
 	return nil, fmt.Errorf("no mapping found for tier %q in ModelTierConfig %q", tier, config.Name)
 }
+
+// ResolveFallbacks resolves a tier's fallbacks, in order. A fallback naming
+// another tier resolves to that tier's mapping, without its own fallbacks.
+func ResolveFallbacks(config *corev1alpha1.ModelTierConfig, tier corev1alpha1.ModelTier) ([]*ResolvedModel, error) {
+	var mapping *corev1alpha1.TierMapping
+	for i := range config.Spec.Tiers {
+		if config.Spec.Tiers[i].Tier == tier {
+			mapping = &config.Spec.Tiers[i]
+			break
+		}
+	}
+	if mapping == nil {
+		return nil, fmt.Errorf("no mapping found for tier %q in ModelTierConfig %q", tier, config.Name)
+	}
+
+	var fallbacks []*ResolvedModel
+	for i, fb := range mapping.Fallbacks {
+		switch {
+		case fb.Tier != "" && (fb.Provider != "" || fb.Model != ""):
+			return nil, fmt.Errorf("tier %q fallback %d: set either tier or provider and model", tier, i)
+		case fb.Tier != "":
+			if fb.Tier == tier {
+				return nil, fmt.Errorf("tier %q fallback %d: a tier can't fall back to itself", tier, i)
+			}
+			resolved, err := ResolveTierFromConfig(config, fb.Tier)
+			if err != nil {
+				return nil, fmt.Errorf("tier %q fallback %d: %w", tier, i, err)
+			}
+			fallbacks = append(fallbacks, resolved)
+		case fb.Provider == "" || fb.Model == "":
+			return nil, fmt.Errorf("tier %q fallback %d: provider and model are required", tier, i)
+		default:
+			resolved := &ResolvedModel{
+				Tier:                 tier,
+				Provider:             fb.Provider,
+				Model:                fb.Model,
+				Endpoint:             fb.Endpoint,
+				Auth:                 fb.Auth,
+				MaxTokens:            fb.MaxTokens,
+				CostPerMillionInput:  fb.CostPerMillionInput,
+				CostPerMillionOutput: fb.CostPerMillionOutput,
+				FullModelString:      fmt.Sprintf("%s/%s", fb.Provider, fb.Model),
+			}
+			if resolved.Auth == nil {
+				resolved.Auth = config.Spec.DefaultAuth
+			}
+			fallbacks = append(fallbacks, resolved)
+		}
+	}
+	return fallbacks, nil
+}
@@ -117,3 +117,77 @@ func TestResolveTierFromConfig_NotFound(t *testing.T) {
 		t.Error("expected error for missing tier")
 	}
 }
+
+func TestResolveFallbacks(t *testing.T) {
+	config := &corev1alpha1.ModelTierConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: corev1alpha1.ModelTierConfigSpec{
+			DefaultAuth: &corev1alpha1.ProviderAuthSpec{Type: corev1alpha1.AuthAPIKey, SecretRef: "llm-api-key"},
+			Tiers: []corev1alpha1.TierMapping{
+				{
+					Tier:     corev1alpha1.ModelTierReasoning,
+					Provider: "anthropic",
+					Model:    "claude-opus-4",
+					Fallbacks: []corev1alpha1.ModelFallback{
+						{Provider: "openai", Model: "gpt-4o", Endpoint: "https://llm.example.com"},
+						{Tier: corev1alpha1.ModelTierStandard},
+					},
+				},
+				{Tier: corev1alpha1.ModelTierStandard, Provider: "anthropic", Model: "claude-sonnet-4"},
+			},
+		},
+	}
+
+	fallbacks, err := ResolveFallbacks(config, corev1alpha1.ModelTierReasoning)
+	if err != nil {
+		t.Fatalf("ResolveFallbacks() error = %v", err)
+	}
+	if len(fallbacks) != 2 {
+		t.Fatalf("got %d fallbacks, want 2", len(fallbacks))
+	}
+	if fallbacks[0].FullModelString != "openai/gpt-4o" || fallbacks[0].Endpoint != "https://llm.example.com" {
+		t.Errorf("fallbacks[0] = %+v", fallbacks[0])
+	}
+	if fallbacks[0].Auth == nil || fallbacks[0].Auth.SecretRef != "llm-api-key" {
+		t.Error("expected the provider fallback to inherit default auth")
+	}
+	if fallbacks[1].FullModelString != "anthropic/claude-sonnet-4" || fallbacks[1].Tier != corev1alpha1.ModelTierStandard {
+		t.Errorf("fallbacks[1] = %+v", fallbacks[1])
+	}
+
+	// A tier without fallbacks has none
+	if fallbacks, err := ResolveFallbacks(config, corev1alpha1.ModelTierStandard); err != nil || len(fallbacks) != 0 {
+		t.Errorf("standard tier: got %v, %v", fallbacks, err)
+	}
+}
+
+func TestResolveFallbacks_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback corev1alpha1.ModelFallback
+	}{
+		{"tier and model", corev1alpha1.ModelFallback{Tier: corev1alpha1.ModelTierFast, Model: "gpt-4o"}},
+		{"itself", corev1alpha1.ModelFallback{Tier: corev1alpha1.ModelTierStandard}},
+		{"missing tier", corev1alpha1.ModelFallback{Tier: corev1alpha1.ModelTierReasoning}},
+		{"no model", corev1alpha1.ModelFallback{Provider: "openai"}},
+	}
+	for _, tt := range tests {
+		config := &corev1alpha1.ModelTierConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: corev1alpha1.ModelTierConfigSpec{
+				Tiers: []corev1alpha1.TierMapping{
+					{Tier: corev1alpha1.ModelTierFast, Provider: "anthropic", Model: "haiku"},
+					{
+						Tier:      corev1alpha1.ModelTierStandard,
+						Provider:  "anthropic",
+						Model:     "sonnet",
+						Fallbacks: []corev1alpha1.ModelFallback{tt.fallback},
+					},
+				},
+			},
+		}
+		if _, err := ResolveFallbacks(config, corev1alpha1.ModelTierStandard); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
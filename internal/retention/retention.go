@@ -219,7 +219,8 @@ func isTerminal(phase corev1alpha1.RunPhase) bool {
 	case corev1alpha1.RunPhaseSucceeded,
 		corev1alpha1.RunPhaseFailed,
 		corev1alpha1.RunPhaseEscalated,
-		corev1alpha1.RunPhaseBlocked:
+		corev1alpha1.RunPhaseBlocked,
+		corev1alpha1.RunPhaseAborted:
 		return true
 	default:
 		return false
@@ -0,0 +1,96 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AbortError is the context cancellation cause for a run aborted on request.
+type AbortError struct {
+	// By identifies who requested the abort.
+	By string
+}
+
+func (e *AbortError) Error() string {
+	return "run aborted by " + e.By
+}
+
+// inflightRun is an executing run's agent and abort function.
+type inflightRun struct {
+	agent  string
+	cancel context.CancelCauseFunc
+}
+
+// abortedBy returns who aborted the run if ctx was cancelled by Abort.
+func abortedBy(ctx context.Context) (string, bool) {
+	var ae *AbortError
+	if errors.As(context.Cause(ctx), &ae) {
+		return ae.By, true
+	}
+	return "", false
+}
+
+// Abort cancels an in-flight run executing in this process. The run finalizes
+// with phase Aborted and the RunConfig Cleanup hook still runs, so dynamic
+// credentials are revoked. Returns false if no such run is executing here.
+func (r *Runner) Abort(run types.NamespacedName, by string) bool {
+	if by == "" {
+		by = "unknown"
+	}
+
+	r.mu.Lock()
+	inflight, ok := r.inflight[run]
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	r.log.Info("aborting agent run", "run", run.String(), "abortedBy", by)
+	inflight.cancel(&AbortError{By: by})
+	return true
+}
+
+// AbortAgent aborts every run of an agent executing in this process, as
+// Abort does. Returns the names of the aborted runs.
+func (r *Runner) AbortAgent(agent types.NamespacedName, by string) []string {
+	r.mu.Lock()
+	var runs []types.NamespacedName
+	for run, inflight := range r.inflight {
+		if run.Namespace == agent.Namespace && inflight.agent == agent.Name {
+			runs = append(runs, run)
+		}
+	}
+	r.mu.Unlock()
+
+	var aborted []string
+	for _, run := range runs {
+		if r.Abort(run, by) {
+			aborted = append(aborted, run.Name)
+		}
+	}
+	return aborted
+}
+
+func (r *Runner) registerInflight(run types.NamespacedName, agent string, cancel context.CancelCauseFunc) {
+	r.mu.Lock()
+	r.inflight[run] = inflightRun{agent: agent, cancel: cancel}
+	r.mu.Unlock()
+}
+
+func (r *Runner) deregisterInflight(run types.NamespacedName) {
+	r.mu.Lock()
+	delete(r.inflight, run)
+	r.mu.Unlock()
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/resolver"
+)
+
+func TestAbort_CancelsInflightRun(t *testing.T) {
+	r := NewRunner(nil, nil, logr.Discard())
+	key := types.NamespacedName{Namespace: "agents", Name: "watchman-abc12"}
+
+	if r.Abort(key, "alice@example.com") {
+		t.Fatal("Abort returned true for a run that is not executing")
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	r.registerInflight(key, "watchman", cancel)
+
+	if !r.Abort(key, "alice@example.com") {
+		t.Fatal("Abort returned false for an in-flight run")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("run context not cancelled")
+	}
+	by, ok := abortedBy(ctx)
+	if !ok || by != "alice@example.com" {
+		t.Errorf("abortedBy = %q, %v; want alice@example.com, true", by, ok)
+	}
+
+	r.deregisterInflight(key)
+	if r.Abort(key, "alice@example.com") {
+		t.Error("Abort returned true after the run was deregistered")
+	}
+}
+
+func TestCreateInflightRun_RegistersBeforeCreate(t *testing.T) {
+	var r *Runner
+	var registered bool
+	c := fake.NewClientBuilder().WithScheme(newTestClient(t).Scheme()).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			r.mu.Lock()
+			_, registered = r.inflight[client.ObjectKeyFromObject(obj)]
+			r.mu.Unlock()
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	r = NewRunner(c, nil, logr.Discard())
+
+	agent := planTestAgent("")
+	run := r.createLegatorRun(agent, &assembler.AssembledAgent{Model: &resolver.ResolvedModel{}}, corev1alpha1.RunTriggerManual)
+	_, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	key, err := r.createInflightRun(context.Background(), run, agent.Name, cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !registered {
+		t.Error("the run was not in flight when its LegatorRun was created")
+	}
+	if !r.Abort(key, "alice@example.com") {
+		t.Error("Abort returned false for a created run")
+	}
+
+	// A run that fails to be created is not left registered
+	r.deregisterInflight(key)
+	if _, err := r.createInflightRun(context.Background(), run.DeepCopy(), agent.Name, cancel); err == nil {
+		t.Fatal("expected creating the same run twice to fail")
+	}
+	if len(r.inflight) != 0 {
+		t.Errorf("expected no runs in flight, got %v", r.inflight)
+	}
+}
+
+func TestAbortAgent_CancelsOnlyThatAgentsRuns(t *testing.T) {
+	r := NewRunner(nil, nil, logr.Discard())
+	agent := types.NamespacedName{Namespace: "agents", Name: "watchman"}
+
+	ctxA, cancelA := context.WithCancelCause(context.Background())
+	defer cancelA(nil)
+	r.registerInflight(types.NamespacedName{Namespace: "agents", Name: "watchman-abc12"}, "watchman", cancelA)
+
+	ctxB, cancelB := context.WithCancelCause(context.Background())
+	defer cancelB(nil)
+	r.registerInflight(types.NamespacedName{Namespace: "agents", Name: "forge-def34"}, "forge", cancelB)
+
+	aborted := r.AbortAgent(agent, "scheduler")
+	if len(aborted) != 1 || aborted[0] != "watchman-abc12" {
+		t.Errorf("aborted = %v, want [watchman-abc12]", aborted)
+	}
+	if ctxA.Err() == nil {
+		t.Error("watchman run not cancelled")
+	}
+	if ctxB.Err() != nil {
+		t.Error("forge run cancelled by another agent's abort")
+	}
+}
+
+func TestAbortedBy_TimeoutIsNotAbort(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := abortedBy(ctx); ok {
+		t.Error("plain cancellation reported as abort")
+	}
+}
+
+func TestMarkAborted(t *testing.T) {
+	result := &conversationResult{phase: corev1alpha1.RunPhaseRunning}
+	result.markAborted("cli:bob")
+
+	if result.phase != corev1alpha1.RunPhaseAborted {
+		t.Errorf("phase = %s, want Aborted", result.phase)
+	}
+	if result.abortedBy != "cli:bob" {
+		t.Errorf("abortedBy = %q, want cli:bob", result.abortedBy)
+	}
+	if !corev1alpha1.RunPhaseAborted.IsTerminal() {
+		t.Error("Aborted should be a terminal phase")
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/reporter"
+)
+
+// DailyCostCeilingAnnotation on a Namespace sets the most its agent runs may
+// spend per UTC day, in USD (e.g. "25.00"). It overrides
+// RunConfig.DailyCostCeiling.
+const DailyCostCeilingAnnotation = "legator.io/daily-cost-ceiling"
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// addCost adds the cost of an LLM call to the run's total and its model's.
+func (c *conversationResult) addCost(model string, usd float64) {
+	if usd <= 0 {
+		return
+	}
+	if c.costByModel == nil {
+		c.costByModel = make(map[string]float64)
+	}
+	c.cost += usd
+	c.costByModel[model] += usd
+}
+
+// estimatedCost is the run's cost so far, formatted for UsageSummary, or ""
+// if none of its models are priced.
+func (c *conversationResult) estimatedCost() string {
+	if c.cost <= 0 {
+		return ""
+	}
+	return reporter.FormatCost(c.cost)
+}
+
+// costLimits are the dollar limits a conversation is held to.
+type costLimits struct {
+	// budget is the agent's per-run costBudget; 0 means none.
+	budget float64
+
+	// ceiling is the namespace's daily cost ceiling; 0 means none.
+	ceiling   float64
+	namespace string
+
+	// spent is what the namespace's other runs had spent today when the
+	// limits were resolved, or spentErr why it couldn't be totalled.
+	spent    float64
+	spentErr error
+}
+
+// newCostLimits resolves the limits for a run of the agent. The namespace's
+// spend is totalled once, here, excluding the named run; spend by runs in
+// flight alongside this one is not counted after that.
+func (r *Runner) newCostLimits(ctx context.Context, agent *corev1alpha1.LegatorAgent, cfg RunConfig, runName string) *costLimits {
+	limits := &costLimits{
+		budget:    reporter.ParseCost(agent.Spec.Model.CostBudget),
+		ceiling:   r.dailyCostCeiling(ctx, agent.Namespace, cfg.DailyCostCeiling),
+		namespace: agent.Namespace,
+	}
+	if limits.ceiling > 0 {
+		limits.spent, limits.spentErr = r.spentToday(ctx, agent.Namespace, runName)
+		if limits.spentErr != nil {
+			r.log.Error(limits.spentErr, "failed to total today's spend", "namespace", agent.Namespace)
+		}
+	}
+	return limits
+}
+
+// costExceeded reports why the run may not make another LLM call, or "" if it
+// may. runCost is what the run has spent so far.
+func (r *Runner) costExceeded(limits *costLimits, runCost float64) string {
+	if limits.budget > 0 && runCost >= limits.budget {
+		return fmt.Sprintf("cost budget exhausted: %s/%s used",
+			reporter.FormatCost(runCost), reporter.FormatCost(limits.budget))
+	}
+	if limits.ceiling <= 0 {
+		return ""
+	}
+	if limits.spentErr != nil {
+		// Fail closed: a ceiling that can't be checked can't be enforced
+		return fmt.Sprintf("namespace %s daily cost ceiling cannot be checked: %v", limits.namespace, limits.spentErr)
+	}
+	if spent := limits.spent + runCost; spent >= limits.ceiling {
+		return fmt.Sprintf("namespace %s daily cost ceiling reached: %s/%s spent today",
+			limits.namespace, reporter.FormatCost(spent), reporter.FormatCost(limits.ceiling))
+	}
+	return ""
+}
+
+// dailyCostCeiling is the namespace's daily cost ceiling: its annotation if
+// set, otherwise the default.
+func (r *Runner) dailyCostCeiling(ctx context.Context, namespace string, def float64) float64 {
+	if r.client == nil {
+		return def
+	}
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		r.log.V(1).Info("namespace not readable, using the default daily cost ceiling",
+			"namespace", namespace,
+			"error", err.Error(),
+		)
+		return def
+	}
+	if v, ok := ns.Annotations[DailyCostCeilingAnnotation]; ok {
+		return reporter.ParseCost(v)
+	}
+	return def
+}
+
+// spentToday totals the estimated cost of the namespace's runs that
+// started since midnight UTC, excluding the named run. In-flight runs count
+// with their spend so far.
+func (r *Runner) spentToday(ctx context.Context, namespace, exclude string) (float64, error) {
+	if r.client == nil {
+		return 0, nil
+	}
+	runs := &corev1alpha1.LegatorRunList{}
+	if err := r.client.List(ctx, runs, client.InNamespace(namespace)); err != nil {
+		return 0, fmt.Errorf("list LegatorRuns: %w", err)
+	}
+
+	midnight := time.Now().UTC().Truncate(24 * time.Hour)
+	var spent float64
+	for i := range runs.Items {
+		run := &runs.Items[i]
+		started := run.CreationTimestamp.Time
+		if run.Status.StartTime != nil {
+			started = run.Status.StartTime.Time
+		}
+		if run.Name == exclude || run.Status.Usage == nil || started.Before(midnight) {
+			continue
+		}
+		spent += reporter.ParseCost(run.Status.Usage.EstimatedCost)
+	}
+	return spent, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+func costTestRun(name string, started time.Time, cost string) *corev1alpha1.LegatorRun {
+	return &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman"},
+		Status: corev1alpha1.LegatorRunStatus{
+			StartTime: &metav1.Time{Time: started},
+			Usage:     &corev1alpha1.UsageSummary{EstimatedCost: cost},
+		},
+	}
+}
+
+func TestConversationLoop_CostBudget(t *testing.T) {
+	// Each call is 100k input tokens at $3/M: $0.30
+	toolCall := &provider.CompletionResponse{
+		ToolCalls: []provider.ToolCall{{ID: "c1", Name: "kubectl.get", Args: map[string]interface{}{"resource": "pods"}}},
+		Usage:     provider.UsageInfo{InputTokens: 100000},
+	}
+	mock := provider.NewMockProvider([]*provider.CompletionResponse{toolCall, toolCall, toolCall}, nil)
+
+	agent := planTestAgent("")
+	agent.Spec.Model.TokenBudget = 1000000
+	agent.Spec.Model.CostBudget = "0.50"
+	result := (&loopTest{
+		cfg:     RunConfig{Provider: mock},
+		tools:   []tools.Tool{&trackingTool{name: "kubectl.get", tracker: &concurrencyTracker{}}},
+		agent:   agent,
+		actions: planTestActions,
+		assembled: &assembler.AssembledAgent{Prompt: "check", Model: &resolver.ResolvedModel{
+			Model: "sonnet", FullModelString: "anthropic/sonnet", CostPerMillionInput: "3.00", CostPerMillionOutput: "15.00",
+		}},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseFailed || !strings.Contains(result.report, "cost budget exhausted: $0.60/$0.50") {
+		t.Fatalf("expected the cost budget to stop the run, got %s: %s", result.phase, result.report)
+	}
+	if mock.CallCount() != 2 {
+		t.Errorf("expected 2 LLM calls before the budget ran out, got %d", mock.CallCount())
+	}
+	if result.estimatedCost() != "$0.60" || result.costByModel["anthropic/sonnet"] != result.cost {
+		t.Errorf("unexpected cost %s, by model %v", result.estimatedCost(), result.costByModel)
+	}
+}
+
+func TestCostExceeded_DailyCeiling(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "agents",
+		Annotations: map[string]string{DailyCostCeilingAnnotation: "1.00"},
+	}}
+	c := newTestClient(t, ns,
+		costTestRun("watchman-1", now, "$0.40"),
+		costTestRun("watchman-2", now, "$0.30"),
+		costTestRun("watchman-old", now.Add(-48*time.Hour), "$5.00"),
+		costTestRun("watchman-3", now, "$0.20"), // the run being checked
+	)
+	r := NewRunner(c, nil, logr.Discard())
+	agent := &corev1alpha1.LegatorAgent{ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"}}
+
+	// The annotation overrides the default
+	limits := r.newCostLimits(ctx, agent, RunConfig{DailyCostCeiling: 100}, "watchman-3")
+	if limits.ceiling != 1 {
+		t.Fatalf("ceiling = %v, want 1", limits.ceiling)
+	}
+	if reason := r.costExceeded(limits, 0.2); reason != "" {
+		t.Errorf("$0.90 of $1.00 should be allowed, got %q", reason)
+	}
+	reason := r.costExceeded(limits, 0.35)
+	if !strings.Contains(reason, "namespace agents daily cost ceiling reached: $1.05/$1.00 spent today") {
+		t.Errorf("unexpected reason %q", reason)
+	}
+}
+
+func TestCostExceeded_SpendTotalledOnce(t *testing.T) {
+	ctx := context.Background()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "agents",
+		Annotations: map[string]string{DailyCostCeilingAnnotation: "1.00"},
+	}}
+	var lists int
+	var listErr error
+	c := fake.NewClientBuilder().WithScheme(newTestClient(t).Scheme()).
+		WithObjects(ns, costTestRun("watchman-1", time.Now(), "$0.40")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				if listErr != nil {
+					return listErr
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+	r := NewRunner(c, nil, logr.Discard())
+	agent := &corev1alpha1.LegatorAgent{ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"}}
+
+	limits := r.newCostLimits(ctx, agent, RunConfig{}, "watchman-2")
+	for _, runCost := range []float64{0.1, 0.2, 0.3} {
+		if reason := r.costExceeded(limits, runCost); reason != "" {
+			t.Errorf("$%.2f should be allowed, got %q", 0.4+runCost, reason)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("expected the namespace's runs to be listed once, got %d", lists)
+	}
+
+	// A ceiling that can't be checked stops the run
+	listErr = errors.New("etcd unavailable")
+	limits = r.newCostLimits(ctx, agent, RunConfig{}, "watchman-2")
+	if reason := r.costExceeded(limits, 0); !strings.Contains(reason, "daily cost ceiling cannot be checked") {
+		t.Errorf("expected the run to fail closed, got %q", reason)
+	}
+}
+
+func TestDailyCostCeiling_Default(t *testing.T) {
+	c := newTestClient(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "agents"}})
+	r := NewRunner(c, nil, logr.Discard())
+	if got := r.dailyCostCeiling(context.Background(), "agents", 25); got != 25 {
+		t.Errorf("dailyCostCeiling = %v, want the default 25", got)
+	}
+	if got := r.dailyCostCeiling(context.Background(), "missing", 25); got != 25 {
+		t.Errorf("dailyCostCeiling for an unreadable namespace = %v, want the default 25", got)
+	}
+}
+
+func TestFinalizeRun_RecordsCost(t *testing.T) {
+	ctx := context.Background()
+	run := costTestRun("watchman-1", time.Now(), "")
+	c := newTestClient(t, run)
+	r := NewRunner(c, nil, logr.Discard())
+
+	result := &conversationResult{phase: corev1alpha1.RunPhaseSucceeded}
+	result.addCost("anthropic/sonnet", 0.105)
+	r.finalizeRun(ctx, run, result, time.Now(), planTestAgent(""), nil)
+
+	got := &corev1alpha1.LegatorRun{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(run), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Usage == nil || got.Status.Usage.EstimatedCost != "$0.10" && got.Status.Usage.EstimatedCost != "$0.11" {
+		t.Errorf("expected the estimated cost to be recorded, got %+v", got.Status.Usage)
+	}
+}
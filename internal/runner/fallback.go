@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/metrics"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/telemetry"
+)
+
+// ModelFallback is a model to fail over to when an LLM call fails.
+type ModelFallback struct {
+	// Model is the resolved fallback model.
+	Model *resolver.ResolvedModel
+
+	// Provider calls the fallback model.
+	Provider provider.Provider
+}
+
+// modelChain is a run's primary model followed by its fallbacks. A run
+// that fails over stays on the fallback for its remaining iterations.
+type modelChain struct {
+	models  []ModelFallback
+	current int
+}
+
+func newModelChain(primary *resolver.ResolvedModel, p provider.Provider, fallbacks []ModelFallback) *modelChain {
+	models := []ModelFallback{{Model: primary, Provider: p}}
+	for _, fb := range fallbacks {
+		if fb.Model != nil && fb.Provider != nil {
+			models = append(models, fb)
+		}
+	}
+	return &modelChain{models: models}
+}
+
+// complete calls the current model, failing over down the chain while the
+// model is unavailable (see provider.Unavailable). Other errors, such as a
+// rejected request, and the run's own context ending fail the call. It
+// returns the model that answered and its still-open LLM span.
+func (r *Runner) complete(
+	ctx context.Context,
+	chain *modelChain,
+	req *provider.CompletionRequest,
+	iteration int32,
+	agentName string,
+) (*provider.CompletionResponse, *resolver.ResolvedModel, trace.Span, error) {
+	for {
+		m := chain.models[chain.current]
+		req.Model = m.Model.Model
+
+		llmCtx, llmSpan := telemetry.StartLLMCallSpan(ctx, m.Model.Model, m.Model.Provider, int(iteration))
+		if chain.current > 0 {
+			telemetry.SetLLMFallback(llmSpan, chain.current)
+		}
+		resp, err := m.Provider.Complete(llmCtx, req)
+		if err == nil {
+			return resp, m.Model, llmSpan, nil
+		}
+		llmSpan.RecordError(err)
+		llmSpan.End()
+
+		if len(chain.models) > 1 {
+			err = fmt.Errorf("%s: %w", m.Model.FullModelString, err)
+		}
+		if ctx.Err() != nil || !provider.Unavailable(err) || chain.current == len(chain.models)-1 {
+			return nil, nil, nil, err
+		}
+
+		chain.current++
+		next := chain.models[chain.current].Model
+		r.log.Info("LLM call failed, failing over to the next model",
+			"agent", agentName,
+			"iteration", iteration+1,
+			"from", m.Model.FullModelString,
+			"to", next.FullModelString,
+			"error", err.Error(),
+		)
+		metrics.RecordModelFallback(agentName, m.Model.FullModelString, next.FullModelString)
+	}
+}
+
+// modelsUsed groups the models that answered a run's LLM calls, one entry
+// per iteration, into spans of consecutive iterations.
+func modelsUsed(models []string) []corev1alpha1.ModelSpan {
+	var spans []corev1alpha1.ModelSpan
+	for i, m := range models {
+		iteration := int32(i + 1)
+		if n := len(spans); n > 0 && spans[n-1].Model == m {
+			spans[n-1].LastIteration = iteration
+			continue
+		}
+		spans = append(spans, corev1alpha1.ModelSpan{Model: m, FirstIteration: iteration, LastIteration: iteration})
+	}
+	return spans
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+func TestConversationLoop_FailsOver(t *testing.T) {
+	primary := provider.NewMockProvider([]*provider.CompletionResponse{nil}, []error{&provider.APIError{Provider: "anthropic", StatusCode: 503, Body: "overloaded"}})
+	fallback := provider.NewMockProviderWithToolCalls([]provider.ToolCall{
+		{ID: "c1", Name: "kubectl.get", Args: map[string]interface{}{"resource": "pods"}},
+	}, "all healthy")
+
+	result := (&loopTest{
+		cfg: RunConfig{Provider: primary, Fallbacks: []ModelFallback{{
+			Model:    &resolver.ResolvedModel{Provider: "openai", Model: "gpt-4o", FullModelString: "openai/gpt-4o"},
+			Provider: fallback,
+		}}},
+		tools: []tools.Tool{&trackingTool{name: "kubectl.get", tracker: &concurrencyTracker{}}},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseSucceeded || result.report != "all healthy" {
+		t.Fatalf("expected the fallback to complete the run, got %s: %s", result.phase, result.report)
+	}
+	// The run stays on the fallback once it fails over
+	if primary.CallCount() != 1 || fallback.CallCount() != 2 {
+		t.Errorf("expected 1 primary and 2 fallback calls, got %d and %d", primary.CallCount(), fallback.CallCount())
+	}
+	if model := fallback.Calls()[0].Model; model != "gpt-4o" {
+		t.Errorf("fallback request model = %q, want gpt-4o", model)
+	}
+	if got := modelsUsed(result.models); len(got) != 1 || got[0].Model != "openai/gpt-4o" {
+		t.Errorf("modelsUsed = %+v", got)
+	}
+}
+
+func TestConversationLoop_SkipsOpenCircuit(t *testing.T) {
+	breaker := provider.NewCircuitBreaker(1, time.Minute)
+	breaker.Record(errors.New("connection refused"))
+	primary := provider.NewMockProviderSimple("unreachable")
+	fallback := provider.NewMockProviderSimple("all healthy")
+
+	result := (&loopTest{
+		cfg: RunConfig{Provider: provider.WithCircuitBreaker(primary, breaker), Fallbacks: []ModelFallback{{
+			Model:    &resolver.ResolvedModel{Provider: "anthropic", Model: "claude-sonnet-4", FullModelString: "anthropic/claude-sonnet-4"},
+			Provider: fallback,
+		}}},
+		tools: []tools.Tool{&trackingTool{name: "kubectl.get", tracker: &concurrencyTracker{}}},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseSucceeded || primary.CallCount() != 0 {
+		t.Errorf("expected the open circuit to skip the primary model, got %s with %d calls", result.phase, primary.CallCount())
+	}
+}
+
+func TestConversationLoop_DoesNotFailOverOnBadRequest(t *testing.T) {
+	primary := provider.NewMockProvider([]*provider.CompletionResponse{nil},
+		[]error{&provider.APIError{Provider: "anthropic", StatusCode: 400, Body: "prompt is too long"}})
+	fallback := provider.NewMockProviderSimple("all healthy")
+
+	result := (&loopTest{
+		cfg: RunConfig{Provider: primary, Fallbacks: []ModelFallback{{
+			Model:    &resolver.ResolvedModel{Provider: "openai", Model: "gpt-4o", FullModelString: "openai/gpt-4o"},
+			Provider: fallback,
+		}}},
+		tools: []tools.Tool{&trackingTool{name: "kubectl.get", tracker: &concurrencyTracker{}}},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseFailed || fallback.CallCount() != 0 {
+		t.Errorf("a rejected request should fail the run without failing over, got %s with %d fallback calls",
+			result.phase, fallback.CallCount())
+	}
+	if !strings.Contains(result.report, "anthropic API returned 400") {
+		t.Errorf("expected the primary model's error in the report, got %q", result.report)
+	}
+}
+
+func TestConversationLoop_AllModelsFail(t *testing.T) {
+	primary := provider.NewMockProvider([]*provider.CompletionResponse{nil}, []error{&provider.APIError{Provider: "anthropic", StatusCode: 503, Body: "overloaded"}})
+	fallback := provider.NewMockProvider([]*provider.CompletionResponse{nil}, []error{errors.New("401 unauthorized")})
+
+	result := (&loopTest{
+		cfg: RunConfig{Provider: primary, Fallbacks: []ModelFallback{{
+			Model:    &resolver.ResolvedModel{Provider: "openai", Model: "gpt-4o", FullModelString: "openai/gpt-4o"},
+			Provider: fallback,
+		}}},
+		tools: []tools.Tool{&trackingTool{name: "kubectl.get", tracker: &concurrencyTracker{}}},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseFailed {
+		t.Fatalf("expected Failed, got %s", result.phase)
+	}
+	if !strings.Contains(result.report, "LLM call failed: openai/gpt-4o: 401 unauthorized") {
+		t.Errorf("expected the last model's error in the report, got %q", result.report)
+	}
+}
+
+func TestModelsUsed(t *testing.T) {
+	tests := []struct {
+		models []string
+		want   []corev1alpha1.ModelSpan
+	}{
+		{nil, nil},
+		{[]string{"anthropic/opus", "anthropic/opus"}, []corev1alpha1.ModelSpan{
+			{Model: "anthropic/opus", FirstIteration: 1, LastIteration: 2},
+		}},
+		{[]string{"anthropic/opus", "openai/gpt-4o", "openai/gpt-4o"}, []corev1alpha1.ModelSpan{
+			{Model: "anthropic/opus", FirstIteration: 1, LastIteration: 1},
+			{Model: "openai/gpt-4o", FirstIteration: 2, LastIteration: 3},
+		}},
+	}
+	for _, tt := range tests {
+		if got := modelsUsed(tt.models); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("modelsUsed(%v) = %+v, want %+v", tt.models, got, tt.want)
+		}
+	}
+}
+
+func TestFinalizeRun_RecordsModelsUsed(t *testing.T) {
+	ctx := context.Background()
+	run := &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman-1", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman", ModelUsed: "anthropic/opus"},
+	}
+	c := newTestClient(t, run)
+	r := NewRunner(c, nil, logr.Discard())
+
+	result := &conversationResult{
+		phase:      corev1alpha1.RunPhaseSucceeded,
+		iterations: 2,
+		models:     []string{"anthropic/opus", "openai/gpt-4o"},
+	}
+	r.finalizeRun(ctx, run, result, time.Now(), planTestAgent(""), nil)
+
+	got := &corev1alpha1.LegatorRun{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(run), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.ModelUsed != "anthropic/opus" {
+		t.Errorf("the spec should keep the model the run started with, got %q", got.Spec.ModelUsed)
+	}
+	want := []corev1alpha1.ModelSpan{
+		{Model: "anthropic/opus", FirstIteration: 1, LastIteration: 1},
+		{Model: "openai/gpt-4o", FirstIteration: 2, LastIteration: 2},
+	}
+	if !reflect.DeepEqual(got.Status.ModelsUsed, want) {
+		t.Errorf("ModelsUsed = %+v, want %+v", got.Status.ModelsUsed, want)
+	}
+	if got.Status.Phase != corev1alpha1.RunPhaseSucceeded {
+		t.Errorf("expected the status to be finalized too, got phase %q", got.Status.Phase)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+)
+
+// reportFindingTool is the built-in tool agents call to record a finding.
+// The runner handles it directly: it never reaches the engine or the
+// tool registry, and is not recorded as an action.
+const reportFindingTool = "report.finding"
+
+// reportFindingDefinition describes the report.finding tool to the LLM.
+func reportFindingDefinition() provider.ToolDefinition {
+	return provider.ToolDefinition{
+		Name: reportFindingTool,
+		Description: "Record a finding for this run's report. Call once per distinct issue you discover. " +
+			"Findings are how your results reach humans, so report them here rather than only in your final text.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"severity": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"info", "warning", "critical"},
+					"description": "How serious the finding is",
+				},
+				"resource": map[string]interface{}{
+					"type":        "string",
+					"description": "The affected resource (e.g. 'deployment/backstage -n backstage')",
+				},
+				"category": map[string]interface{}{
+					"type":        "string",
+					"description": "Short stable label for the kind of issue (e.g. 'crashloop', 'cert-expiry'). Use the same label for the same issue on every run.",
+				},
+				"message": map[string]interface{}{
+					"type":        "string",
+					"description": "What you found",
+				},
+				"remediation": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional suggested fix",
+				},
+			},
+			"required": []string{"severity", "message"},
+		},
+	}
+}
+
+// recordFinding handles a report.finding call, adding the finding to the
+// run. Invalid arguments are returned to the LLM as an error to correct.
+func (r *Runner) recordFinding(result *conversationResult, agent *corev1alpha1.LegatorAgent, tc provider.ToolCall) provider.ToolResult {
+	f, err := parseReportFinding(tc.Args)
+	if err != nil {
+		return provider.ToolResult{
+			ToolCallID: tc.ID,
+			Content:    fmt.Sprintf("ERROR: %v", err),
+			IsError:    true,
+		}
+	}
+
+	result.findings = mergeFinding(result.findings, f)
+	result.reportedFindings = true
+	r.log.V(1).Info("finding reported",
+		"agent", agent.Name,
+		"id", f.ID,
+		"severity", f.Severity,
+		"resource", f.Resource,
+	)
+	return provider.ToolResult{
+		ToolCallID: tc.ID,
+		Content:    fmt.Sprintf("recorded finding %s", f.ID),
+	}
+}
+
+// parseReportFinding builds a RunFinding from report.finding arguments.
+func parseReportFinding(args map[string]interface{}) (corev1alpha1.RunFinding, error) {
+	str := func(key string) string {
+		v, _ := args[key].(string)
+		return strings.TrimSpace(v)
+	}
+
+	f := corev1alpha1.RunFinding{
+		Severity:    corev1alpha1.FindingSeverity(strings.ToLower(str("severity"))),
+		Resource:    str("resource"),
+		Category:    strings.ToLower(str("category")),
+		Message:     str("message"),
+		Remediation: str("remediation"),
+	}
+	switch f.Severity {
+	case corev1alpha1.FindingSeverityInfo, corev1alpha1.FindingSeverityWarning, corev1alpha1.FindingSeverityCritical:
+	default:
+		return f, fmt.Errorf("severity must be one of info, warning, critical (got %q)", f.Severity)
+	}
+	if f.Message == "" {
+		return f, fmt.Errorf("message is required")
+	}
+	f.ID = findingID(f)
+	return f, nil
+}
+
+// findingID derives a stable ID for a finding. The resource and category
+// identify the issue when a category is given; otherwise the normalised
+// message stands in for it, so rewording produces a new ID.
+func findingID(f corev1alpha1.RunFinding) string {
+	key := f.Category
+	if key == "" {
+		key = strings.Join(strings.Fields(strings.ToLower(f.Message)), " ")
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(f.Resource) + "\x00" + key))
+	return "f-" + hex.EncodeToString(sum[:6])
+}
+
+// mergeFinding adds f to findings, replacing an earlier finding with the
+// same ID so repeated reports of one issue within a run collapse.
+func mergeFinding(findings []corev1alpha1.RunFinding, f corev1alpha1.RunFinding) []corev1alpha1.RunFinding {
+	for i := range findings {
+		if findings[i].ID == f.ID {
+			findings[i] = f
+			return findings
+		}
+	}
+	return append(findings, f)
+}
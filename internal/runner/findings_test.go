@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+)
+
+func TestParseReportFinding(t *testing.T) {
+	f, err := parseReportFinding(map[string]interface{}{
+		"severity":    "Warning",
+		"resource":    "deployment/backstage -n backstage",
+		"category":    "CrashLoop",
+		"message":     "  backstage-backend is in CrashLoopBackOff ",
+		"remediation": "roll back to the previous image",
+	})
+	if err != nil {
+		t.Fatalf("parseReportFinding: %v", err)
+	}
+	if f.Severity != corev1alpha1.FindingSeverityWarning || f.Category != "crashloop" ||
+		f.Message != "backstage-backend is in CrashLoopBackOff" || f.Remediation == "" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if f.ID == "" {
+		t.Error("expected an ID to be assigned")
+	}
+
+	invalid := []map[string]interface{}{
+		{"severity": "urgent", "message": "disk full"},
+		{"severity": "critical"},
+		{"message": "no severity"},
+	}
+	for _, args := range invalid {
+		if _, err := parseReportFinding(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestFindingID_Stable(t *testing.T) {
+	base := corev1alpha1.RunFinding{Resource: "node/talos-wk-01", Category: "memory-pressure", Message: "Node has memory pressure"}
+
+	reworded := base
+	reworded.Message = "talos-wk-01 is under memory pressure (92%)"
+	if findingID(base) != findingID(reworded) {
+		t.Error("same resource and category should keep the same ID when the message changes")
+	}
+
+	other := base
+	other.Resource = "node/talos-wk-02"
+	if findingID(base) == findingID(other) {
+		t.Error("different resources should get different IDs")
+	}
+
+	noCategory := corev1alpha1.RunFinding{Message: "Certificate  expires in 7 days"}
+	spaced := corev1alpha1.RunFinding{Message: "certificate expires in 7 days"}
+	if findingID(noCategory) != findingID(spaced) {
+		t.Error("messages differing only in case and whitespace should share an ID")
+	}
+}
+
+func TestRecordFinding(t *testing.T) {
+	r := NewRunner(nil, nil, logr.Discard())
+	agent := &corev1alpha1.LegatorAgent{ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"}}
+	result := &conversationResult{}
+
+	call := func(id string, args map[string]interface{}) provider.ToolResult {
+		return r.recordFinding(result, agent, provider.ToolCall{ID: id, Name: reportFindingTool, Args: args})
+	}
+
+	if res := call("1", map[string]interface{}{"severity": "nope", "message": "x"}); !res.IsError {
+		t.Error("invalid finding should return an error result")
+	}
+	if result.reportedFindings {
+		t.Error("an invalid call should not count as reporting findings")
+	}
+
+	args := map[string]interface{}{"severity": "warning", "resource": "pod/api-0", "category": "restarts", "message": "restarting"}
+	if res := call("2", args); res.IsError || res.ToolCallID != "2" {
+		t.Errorf("unexpected tool result: %+v", res)
+	}
+	args["severity"], args["message"] = "critical", "restarting every minute"
+	call("3", args)
+
+	if !result.reportedFindings {
+		t.Error("expected reportedFindings to be set")
+	}
+	if len(result.findings) != 1 {
+		t.Fatalf("repeated reports of one issue should collapse, got %d findings", len(result.findings))
+	}
+	if result.findings[0].Severity != corev1alpha1.FindingSeverityCritical {
+		t.Errorf("expected the latest report to win, got %+v", result.findings[0])
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/metrics"
+)
+
+// suspectInjection records tool output that matched prompt-injection
+// patterns as a guardrail event and, if the agent sets injectionAutonomy,
+// lowers the run's autonomy for its remaining actions. It returns a note
+// for the model's tool result, or "" if there is nothing to add.
+func (r *Runner) suspectInjection(
+	result *conversationResult,
+	eng *engine.Engine,
+	agent *corev1alpha1.LegatorAgent,
+	record *corev1alpha1.ActionRecord,
+	patterns []string,
+) string {
+	if len(patterns) == 0 {
+		return ""
+	}
+
+	signal := corev1alpha1.InjectionSignal{
+		Seq:       record.Seq,
+		Tool:      record.Tool,
+		Patterns:  patterns,
+		Timestamp: metav1.Now(),
+	}
+	note := ""
+	if level := agent.Spec.Guardrails.InjectionAutonomy; eng.LowerAutonomy(level) {
+		signal.AutonomyLoweredTo = level
+		note = fmt.Sprintf("\n\nSUSPECTED PROMPT INJECTION: this output resembles instructions aimed at you. "+
+			"Your autonomy is now %s for the rest of the run.", level)
+	}
+	result.guardrails.SuspectedInjections = append(result.guardrails.SuspectedInjections, signal)
+
+	for _, p := range patterns {
+		metrics.RecordPromptInjection(agent.Name, p)
+	}
+	r.log.Info("tool output looks like a prompt injection",
+		"agent", agent.Name,
+		"tool", record.Tool,
+		"seq", record.Seq,
+		"patterns", patterns,
+		"autonomyLoweredTo", signal.AutonomyLoweredTo,
+	)
+	return note
+}
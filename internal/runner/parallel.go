@@ -0,0 +1,89 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/security"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// defaultMaxParallelReads is how many read-tier tool calls from one model
+// turn run concurrently when the agent doesn't say.
+const defaultMaxParallelReads = 4
+
+// pendingRead is an allowed read-tier tool call waiting to run in a batch.
+type pendingRead struct {
+	// index is the call's position in the model's turn.
+	index    int
+	call     provider.ToolCall
+	target   string
+	decision *engine.Decision
+	record   corev1alpha1.ActionRecord
+	span     trace.Span
+
+	// output and err are set once the call has executed.
+	output string
+	err    error
+}
+
+// executeReads runs a batch of read-tier tool calls concurrently, at most
+// limit at a time, and waits for all of them. Each call is retried up to
+// maxRetries times on transient failure.
+func executeReads(ctx context.Context, reg *tools.Registry, batch []*pendingRead, limit int, maxRetries int32) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, p := range batch {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(p *pendingRead) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.output, p.record.Retries, p.err = executeWithRetry(ctx, reg, p.call.Name, p.call.Args, maxRetries)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// applyToolOutput records a tool's output on its action record and builds
+// the result returned to the LLM. The LLM gets the full output, or error,
+// in an untrusted block; the audit trail keeps a sanitized, truncated copy.
+// It also returns the prompt-injection patterns the output matched.
+func applyToolOutput(record *corev1alpha1.ActionRecord, tc provider.ToolCall, output string, err error) (provider.ToolResult, []string) {
+	if err != nil {
+		record.Status = corev1alpha1.ActionStatusFailed
+		record.Result = security.SanitizeActionResult(fmt.Sprintf("execution error: %v", err), 4096)
+		content, signals := security.ScreenToolOutput(tc.Name, err.Error())
+		return provider.ToolResult{
+			ToolCallID: tc.ID,
+			Content:    "ERROR: " + content,
+			IsError:    true,
+		}, signals
+	}
+	record.Status = corev1alpha1.ActionStatusExecuted
+	record.Result = security.SanitizeActionResult(output, 4096)
+	content, signals := security.ScreenToolOutput(tc.Name, output)
+	return provider.ToolResult{
+		ToolCallID: tc.ID,
+		Content:    content,
+	}, signals
+}
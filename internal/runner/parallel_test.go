@@ -0,0 +1,137 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// concurrencyTracker records how many tool calls overlap.
+type concurrencyTracker struct {
+	mu     sync.Mutex
+	active int
+	peak   int
+	// seen is the number of calls in flight as each call started.
+	seen []int
+}
+
+// trackingTool is a tool that reports its calls to a shared tracker.
+type trackingTool struct {
+	name    string
+	delay   time.Duration
+	tracker *concurrencyTracker
+}
+
+func (t *trackingTool) Name() string                       { return t.name }
+func (t *trackingTool) Description() string                { return t.name }
+func (t *trackingTool) Parameters() map[string]interface{} { return nil }
+
+func (t *trackingTool) Execute(_ context.Context, args map[string]interface{}) (string, error) {
+	tr := t.tracker
+	tr.mu.Lock()
+	tr.active++
+	tr.peak = max(tr.peak, tr.active)
+	tr.seen = append(tr.seen, tr.active)
+	tr.mu.Unlock()
+
+	time.Sleep(t.delay)
+
+	tr.mu.Lock()
+	tr.active--
+	tr.mu.Unlock()
+	return fmt.Sprintf("%s %v", t.name, args["name"]), nil
+}
+
+// parallelLoop reads three pods, restarts a deployment, then reads another.
+func parallelLoop(maxParallel int32) (*loopTest, *provider.MockProvider, *concurrencyTracker) {
+	tracker := &concurrencyTracker{}
+	call := func(id, tool, name string) provider.ToolCall {
+		return provider.ToolCall{ID: id, Name: tool, Args: map[string]interface{}{"resource": "pods", "name": name}}
+	}
+	mock := provider.NewMockProvider([]*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{
+			call("c1", "kubectl.get", "a"),
+			call("c2", "kubectl.get", "b"),
+			call("c3", "kubectl.get", "c"),
+			call("c4", "kubectl.rollout", "api"),
+			call("c5", "kubectl.get", "d"),
+		}},
+		{Content: "done"},
+	}, []error{nil, nil})
+
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.MaxParallelReads = maxParallel
+	return &loopTest{
+		cfg: RunConfig{Provider: mock},
+		tools: []tools.Tool{
+			&trackingTool{name: "kubectl.get", delay: 30 * time.Millisecond, tracker: tracker},
+			&trackingTool{name: "kubectl.rollout", tracker: tracker},
+		},
+		agent:   agent,
+		actions: planTestActions,
+	}, mock, tracker
+}
+
+func TestConversationLoop_ParallelReads(t *testing.T) {
+	lt, mock, tracker := parallelLoop(4)
+	result := lt.run()
+
+	if tracker.peak != 3 {
+		t.Errorf("expected the three reads before the mutation to overlap, peak concurrency %d", tracker.peak)
+	}
+	if len(result.actions) != 5 {
+		t.Fatalf("expected 5 actions, got %d", len(result.actions))
+	}
+	wantTools := []string{"kubectl.get", "kubectl.get", "kubectl.get", "kubectl.rollout", "kubectl.get"}
+	for i, a := range result.actions {
+		if a.Seq != int32(i+1) || a.Tool != wantTools[i] {
+			t.Errorf("action %d: got seq %d tool %s, want seq %d tool %s", i, a.Seq, a.Tool, i+1, wantTools[i])
+		}
+		if a.Status != corev1alpha1.ActionStatusExecuted {
+			t.Errorf("action %d: expected executed, got %s (%s)", i, a.Status, a.Result)
+		}
+	}
+
+	results := mock.Calls()[1].Messages[2].ToolResults
+	for i, id := range []string{"c1", "c2", "c3", "c4", "c5"} {
+		if results[i].ToolCallID != id {
+			t.Errorf("tool result %d: got %s, want %s", i, results[i].ToolCallID, id)
+		}
+	}
+	if results[3].Content != "<untrusted-tool-output tool=\"kubectl.rollout\">\nkubectl.rollout api\n</untrusted-tool-output>" {
+		t.Errorf("unexpected rollout result %q", results[3].Content)
+	}
+}
+
+func TestConversationLoop_MutationWaitsForReads(t *testing.T) {
+	lt, _, tracker := parallelLoop(4)
+	lt.run()
+	// The rollout is the fourth call to start; nothing else may be running
+	if len(tracker.seen) != 5 || tracker.seen[3] != 1 {
+		t.Errorf("expected the mutation to run alone, observed concurrency per call %v", tracker.seen)
+	}
+}
+
+func TestConversationLoop_SequentialReads(t *testing.T) {
+	lt, _, tracker := parallelLoop(1)
+	lt.run()
+	if tracker.peak != 1 {
+		t.Errorf("maxParallelReads=1 should run calls sequentially, peak concurrency %d", tracker.peak)
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/approval"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/security"
+)
+
+// AnnotationPlanAppliedBy is set on a Plan-mode run once it has been
+// applied, naming the run that applied it. A plan is applied at most once.
+const AnnotationPlanAppliedBy = "legator.io/applied-by"
+
+// planModeInstructions is added to the first user message of a Plan run.
+const planModeInstructions = "\n\nThis is a PLAN run. Read-only tools run normally, but changes are not " +
+	"executed: each change you request that passes guardrails is recorded in a change plan for " +
+	"human review. Investigate as usual, request the changes you would make, and finish with a " +
+	"change plan listing each change in order, why it is needed, and how to verify it."
+
+// planMode reports whether a run records mutations instead of executing them.
+func planMode(agent *corev1alpha1.LegatorAgent, cfg RunConfig) bool {
+	return cfg.Plan || agent.Spec.Mode == corev1alpha1.RunModePlan
+}
+
+// planAction records a mutation as planned and builds the synthetic result
+// telling the model it was deferred.
+func planAction(record *corev1alpha1.ActionRecord, tc provider.ToolCall, decision *engine.Decision) provider.ToolResult {
+	record.Status = corev1alpha1.ActionStatusPlanned
+	record.Result = "deferred: plan run"
+	if decision.NeedsApproval {
+		record.Result += " (needs approval: " + decision.BlockReason + ")"
+	}
+
+	// Args containing secrets are not stored, so the plan can't be applied
+	// with redacted values
+	if data, err := json.Marshal(tc.Args); err == nil && security.Sanitize(string(data)) == string(data) {
+		record.Args = string(data)
+	} else {
+		record.Result += "; arguments not recorded (contain sensitive values)"
+	}
+
+	return provider.ToolResult{
+		ToolCallID: tc.ID,
+		Content: fmt.Sprintf("PLANNED: %s on %s was added to the change plan and has NOT been executed. "+
+			"Assume it has not happened when you continue.", tc.Name, record.Target),
+	}
+}
+
+// preFlightResult converts an engine decision's checks for the audit trail.
+func preFlightResult(d *engine.Decision) *corev1alpha1.PreFlightResult {
+	return &corev1alpha1.PreFlightResult{
+		AutonomyCheck:     d.PreFlight.AutonomyCheck,
+		DataImpactCheck:   d.PreFlight.DataImpactCheck,
+		AllowListCheck:    d.PreFlight.AllowListCheck,
+		DataProtection:    d.PreFlight.DataProtection,
+		ChangeWindowCheck: d.PreFlight.ChangeWindowCheck,
+		PreConditionCheck: d.PreFlight.PreConditionCheck,
+		Reason:            d.PreFlight.Reason,
+	}
+}
+
+// loadPlan fetches a Plan-mode run of agent and checks it can be applied.
+func (r *Runner) loadPlan(ctx context.Context, agent *corev1alpha1.LegatorAgent, name string) (*corev1alpha1.LegatorRun, error) {
+	plan := &corev1alpha1.LegatorRun{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: agent.Namespace, Name: name}, plan); err != nil {
+		return nil, err
+	}
+	switch {
+	case plan.Spec.AgentRef != agent.Name:
+		return nil, fmt.Errorf("run belongs to agent %q", plan.Spec.AgentRef)
+	case plan.Spec.Mode != corev1alpha1.RunModePlan:
+		return nil, fmt.Errorf("not a Plan-mode run")
+	case plan.Status.Phase != corev1alpha1.RunPhaseSucceeded:
+		return nil, fmt.Errorf("plan run is %s, not Succeeded", plan.Status.Phase)
+	case plan.Annotations[AnnotationPlanAppliedBy] != "":
+		return nil, fmt.Errorf("already applied by %s", plan.Annotations[AnnotationPlanAppliedBy])
+	}
+
+	planned := 0
+	for _, a := range plan.Status.Actions {
+		if a.Status != corev1alpha1.ActionStatusPlanned {
+			continue
+		}
+		if a.Args == "" {
+			return nil, fmt.Errorf("planned action %d (%s) has no recorded arguments", a.Seq, a.Tool)
+		}
+		planned++
+	}
+	if planned == 0 {
+		return nil, fmt.Errorf("plan has no planned actions")
+	}
+	return plan, nil
+}
+
+// planGuardrails are the guardrails a plan is applied under: the agent's
+// current guardrails, at the lower of its current autonomy and the ceiling
+// the plan was made under, so a plan can't outlive a cut in autonomy. Every
+// check (change windows, cooldowns, data protection, allow and deny lists)
+// is evaluated again; actions that need approval are approved together by
+// approvePlan.
+func planGuardrails(agent *corev1alpha1.LegatorAgent, plan *corev1alpha1.LegatorRun) *corev1alpha1.GuardrailsSpec {
+	g := agent.Spec.Guardrails.DeepCopy()
+	if plan.Status.Guardrails != nil {
+		g.Autonomy = engine.MinAutonomy(g.Autonomy, plan.Status.Guardrails.AutonomyCeiling)
+	}
+	return g
+}
+
+// approvePlan asks for a single approval covering every planned action that
+// needs one, before any action runs, so an approver sees the whole change.
+// It returns the sequence numbers of the approved actions, or why the plan
+// can't proceed. Without an approval manager nothing is approved, and the
+// apply stops at the first action that needs approval.
+func (r *Runner) approvePlan(
+	ctx context.Context,
+	plan *corev1alpha1.LegatorRun,
+	run *corev1alpha1.LegatorRun,
+	eng *engine.Engine,
+	cfg RunConfig,
+	agent *corev1alpha1.LegatorAgent,
+) (map[int32]bool, string) {
+	var tier corev1alpha1.ActionTier
+	args := map[string]string{}
+	gated := map[int32]bool{}
+	for _, a := range plan.Status.Actions {
+		if a.Status != corev1alpha1.ActionStatusPlanned {
+			continue
+		}
+		d := eng.Evaluate(a.Tool, a.Target)
+		if !d.NeedsApproval {
+			continue
+		}
+		gated[a.Seq] = true
+		args[fmt.Sprintf("action-%d", a.Seq)] = a.Tool + " " + a.Target
+		if tier == "" || d.Tier == corev1alpha1.ActionTierDestructiveMutation {
+			tier = d.Tier
+		}
+	}
+	if len(gated) == 0 || cfg.ApprovalManager == nil {
+		return nil, ""
+	}
+
+	r.log.Info("plan needs approval",
+		"agent", agent.Name,
+		"plan", plan.Name,
+		"actions", len(gated),
+	)
+	approvalResult, approvalErr := cfg.ApprovalManager.RequestApproval(ctx, approval.ApprovalParams{
+		AgentName: agent.Name,
+		RunName:   run.Name,
+		Namespace: agent.Namespace,
+		Tool:      "plan.apply",
+		Tier:      tier,
+		Target:    plan.Name,
+		Description: fmt.Sprintf("Agent %s wants to apply plan %s, including %d actions that need approval",
+			agent.Name, plan.Name, len(gated)),
+		Args:    args,
+		Timeout: agent.Spec.Guardrails.ApprovalTimeout,
+	})
+	if approvalErr != nil || !approvalResult.Approved {
+		if approvalResult != nil && approvalResult.Phase == corev1alpha1.ApprovalPhaseDenied {
+			return nil, fmt.Sprintf("approval denied by %s: %s", approvalResult.DecidedBy, approvalResult.Reason)
+		}
+		return nil, fmt.Sprintf("approval expired or failed: %v", approvalErr)
+	}
+	return gated, ""
+}
+
+// applyPlan executes a plan's planned actions in order, instead of a
+// conversation. The plan is applied as a unit: it stops at the first
+// action that is blocked or fails.
+func (r *Runner) applyPlan(
+	ctx context.Context,
+	plan *corev1alpha1.LegatorRun,
+	run *corev1alpha1.LegatorRun,
+	eng *engine.Engine,
+	cfg RunConfig,
+	agent *corev1alpha1.LegatorAgent,
+	progress *progressRecorder,
+) *conversationResult {
+	result := &conversationResult{
+		phase: corev1alpha1.RunPhaseSucceeded,
+		guardrails: corev1alpha1.GuardrailSummary{
+			AutonomyCeiling: planGuardrails(agent, plan).Autonomy,
+		},
+	}
+
+	if err := r.markPlanApplied(ctx, plan, run.Name); err != nil {
+		result.phase = corev1alpha1.RunPhaseFailed
+		result.report = fmt.Sprintf("plan %s could not be claimed: %v", plan.Name, err)
+		result.err = err
+		return result
+	}
+
+	approved, reason := r.approvePlan(ctx, plan, run, eng, cfg, agent)
+	if reason != "" {
+		result.phase = corev1alpha1.RunPhaseBlocked
+		result.report = fmt.Sprintf("plan %s was not applied: %s", plan.Name, reason)
+		return result
+	}
+
+	var executed int
+	for _, planned := range plan.Status.Actions {
+		if planned.Status != corev1alpha1.ActionStatusPlanned {
+			continue
+		}
+		if by, ok := abortedBy(ctx); ok {
+			result.markAborted(by)
+			return result
+		}
+
+		tc := provider.ToolCall{ID: fmt.Sprintf("plan-%d", planned.Seq), Name: planned.Tool}
+		if err := json.Unmarshal([]byte(planned.Args), &tc.Args); err != nil {
+			result.phase = corev1alpha1.RunPhaseFailed
+			result.report = fmt.Sprintf("plan %s: action %d has invalid arguments: %v", plan.Name, planned.Seq, err)
+			result.err = err
+			return result
+		}
+
+		decision := eng.Evaluate(planned.Tool, planned.Target)
+		eng.CheckPreConditions(ctx, decision, tc.Args)
+		result.guardrails.ChecksPerformed++
+		record := corev1alpha1.ActionRecord{
+			Seq:            int32(len(result.actions) + 1),
+			Timestamp:      metav1.Now(),
+			Tool:           planned.Tool,
+			Target:         planned.Target,
+			Tier:           decision.Tier,
+			PreFlightCheck: preFlightResult(decision),
+		}
+
+		// A precondition may find an action no longer needed
+		if decision.Status == corev1alpha1.ActionStatusSkipped {
+			record.Status = corev1alpha1.ActionStatusSkipped
+			record.Result = decision.BlockReason
+			result.actions = append(result.actions, record)
+			progress.actionRecorded(ctx, result)
+			continue
+		}
+
+		if !decision.Allowed && !(decision.NeedsApproval && approved[planned.Seq]) {
+			record.Status = decision.Status
+			if record.Status == corev1alpha1.ActionStatusPendingApproval {
+				record.Status = corev1alpha1.ActionStatusBlocked
+			}
+			record.Result = decision.BlockReason
+			result.guardrails.ActionsBlocked++
+			result.actions = append(result.actions, record)
+			progress.actionRecorded(ctx, result)
+			result.phase = corev1alpha1.RunPhaseBlocked
+			result.report = fmt.Sprintf("plan %s stopped after %d of its actions: %s on %s is now blocked: %s",
+				plan.Name, executed, planned.Tool, planned.Target, decision.BlockReason)
+			return result
+		}
+
+		output, retries, err := executeWithRetry(ctx, cfg.ToolRegistry, planned.Tool, tc.Args, retryLimit(agent, decision))
+		record.Retries = retries
+		applyToolOutput(&record, tc, output, err)
+		if err == nil && decision.NeedsApproval {
+			record.Status = corev1alpha1.ActionStatusApproved
+		}
+		result.actions = append(result.actions, record)
+		progress.actionRecorded(ctx, result)
+		if err != nil {
+			result.phase = corev1alpha1.RunPhaseFailed
+			result.report = fmt.Sprintf("plan %s stopped after %d of its actions: %s on %s failed: %v",
+				plan.Name, executed, planned.Tool, planned.Target, err)
+			result.err = err
+			return result
+		}
+		if decision.MatchedAction != nil {
+			eng.RecordExecution(decision.MatchedAction.ID, planned.Target)
+		}
+		executed++
+		if note := r.verifyMutation(ctx, tc, decision, eng, cfg, agent, result, progress); note != "" {
+			result.phase = corev1alpha1.RunPhaseFailed
+			result.report = fmt.Sprintf("plan %s stopped after %d of its actions: %s on %s: %s",
+				plan.Name, executed, planned.Tool, planned.Target, strings.TrimSpace(note))
+			return result
+		}
+	}
+
+	result.report = fmt.Sprintf("Applied plan %s: %d actions executed.", plan.Name, executed)
+	return result
+}
+
+// markPlanApplied claims a plan for this run, failing if another run
+// claimed it first.
+func (r *Runner) markPlanApplied(ctx context.Context, plan *corev1alpha1.LegatorRun, runName string) error {
+	base := plan.DeepCopy()
+	if plan.Annotations == nil {
+		plan.Annotations = map[string]string{}
+	}
+	plan.Annotations[AnnotationPlanAppliedBy] = runName
+	return r.client.Patch(ctx, plan, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/approval"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+var planTestActions = map[string]*skill.Action{
+	"restart": {ID: "restart", Tool: "kubectl.rollout", Tier: "service-mutation"},
+}
+
+func planTestAgent(mode corev1alpha1.RunMode) *corev1alpha1.LegatorAgent {
+	return &corev1alpha1.LegatorAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "fixer", Namespace: "agents"},
+		Spec: corev1alpha1.LegatorAgentSpec{
+			Model: corev1alpha1.ModelSpec{TokenBudget: 100000},
+			Guardrails: corev1alpha1.GuardrailsSpec{
+				Autonomy:      corev1alpha1.AutonomySafe,
+				MaxIterations: 5,
+			},
+			Mode: mode,
+		},
+	}
+}
+
+func planTestTools(tracker *concurrencyTracker) []tools.Tool {
+	return []tools.Tool{
+		&trackingTool{name: "kubectl.get", tracker: tracker},
+		&trackingTool{name: "kubectl.rollout", tracker: tracker},
+	}
+}
+
+func TestConversationLoop_PlanMode(t *testing.T) {
+	tracker := &concurrencyTracker{}
+	mock := provider.NewMockProvider([]*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{
+			{ID: "c1", Name: "kubectl.get", Args: map[string]interface{}{"resource": "deployment", "name": "api"}},
+			{ID: "c2", Name: "kubectl.rollout", Args: map[string]interface{}{"action": "restart", "resource": "deployment", "name": "api"}},
+		}},
+		{Content: "Change plan: 1. restart deployment/api"},
+	}, []error{nil, nil})
+
+	result := (&loopTest{
+		cfg:     RunConfig{Provider: mock},
+		tools:   planTestTools(tracker),
+		agent:   planTestAgent(corev1alpha1.RunModePlan),
+		actions: planTestActions,
+	}).run()
+
+	if len(tracker.seen) != 1 {
+		t.Errorf("expected only the read to execute, %d tool calls ran", len(tracker.seen))
+	}
+	if len(result.actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(result.actions))
+	}
+	if result.actions[0].Status != corev1alpha1.ActionStatusExecuted {
+		t.Errorf("read should execute, got %s", result.actions[0].Status)
+	}
+	planned := result.actions[1]
+	if planned.Status != corev1alpha1.ActionStatusPlanned {
+		t.Errorf("mutation should be planned, got %s", planned.Status)
+	}
+	if !strings.Contains(planned.Args, `"action":"restart"`) {
+		t.Errorf("planned action should record its args, got %q", planned.Args)
+	}
+
+	calls := mock.Calls()
+	if !strings.Contains(calls[0].Messages[0].Content, "PLAN run") {
+		t.Error("the model should be told this is a plan run")
+	}
+	if res := calls[1].Messages[2].ToolResults[1]; !strings.HasPrefix(res.Content, "PLANNED:") || res.IsError {
+		t.Errorf("unexpected synthetic result %+v", res)
+	}
+}
+
+func TestConversationLoop_PlanWithBlockedAction(t *testing.T) {
+	mock := provider.NewMockProvider([]*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{
+			{ID: "c1", Name: "kubectl.rollout", Args: map[string]interface{}{"action": "restart", "resource": "deployment", "name": "db"}},
+			{ID: "c2", Name: "kubectl.rollout", Args: map[string]interface{}{"action": "restart", "resource": "deployment", "name": "api"}},
+		}},
+		{Content: "Change plan: 1. restart deployment/api"},
+	}, nil)
+
+	agent := planTestAgent(corev1alpha1.RunModePlan)
+	agent.Spec.Guardrails.DeniedActions = []string{"kubectl.rollout deployment db"}
+	result := (&loopTest{
+		cfg:     RunConfig{Provider: mock},
+		tools:   planTestTools(&concurrencyTracker{}),
+		agent:   agent,
+		actions: planTestActions,
+	}).run()
+
+	if result.actions[0].Status != corev1alpha1.ActionStatusBlocked || result.actions[1].Status != corev1alpha1.ActionStatusPlanned {
+		t.Fatalf("expected one blocked and one planned action, got %+v", result.actions)
+	}
+	if result.phase != corev1alpha1.RunPhaseSucceeded {
+		t.Errorf("a plan with a planned action should succeed, got %s", result.phase)
+	}
+}
+
+func TestConversationLoop_PlanFlagOverridesSpec(t *testing.T) {
+	if !planMode(planTestAgent(""), RunConfig{Plan: true}) {
+		t.Error("RunConfig.Plan should force plan mode")
+	}
+	if planMode(planTestAgent(corev1alpha1.RunModeExecute), RunConfig{}) {
+		t.Error("Execute agents should not plan")
+	}
+}
+
+func TestPlanAction_SkipsSensitiveArgs(t *testing.T) {
+	record := &corev1alpha1.ActionRecord{Target: "db"}
+	tc := provider.ToolCall{ID: "c1", Name: "sql.query", Args: map[string]interface{}{"dsn": "password: super-secret-123!"}}
+	planAction(record, tc, &engine.Decision{Allowed: true})
+	if record.Args != "" {
+		t.Errorf("args with secrets should not be stored, got %q", record.Args)
+	}
+}
+
+func planTestRun(extra ...corev1alpha1.ActionRecord) *corev1alpha1.LegatorRun {
+	return &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "fixer-plan1", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "fixer", Mode: corev1alpha1.RunModePlan},
+		Status: corev1alpha1.LegatorRunStatus{
+			Phase:      corev1alpha1.RunPhaseSucceeded,
+			Guardrails: &corev1alpha1.GuardrailSummary{AutonomyCeiling: corev1alpha1.AutonomySafe},
+			Actions: append([]corev1alpha1.ActionRecord{
+				{Seq: 1, Tool: "kubectl.get", Target: "deployment api", Status: corev1alpha1.ActionStatusExecuted},
+				{Seq: 2, Tool: "kubectl.rollout", Target: "deployment api", Status: corev1alpha1.ActionStatusPlanned,
+					Args: `{"action":"restart","resource":"deployment","name":"api"}`},
+			}, extra...),
+		},
+	}
+}
+
+func TestApplyPlan(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, planTestRun())
+	r := NewRunner(c, nil, logr.Discard())
+
+	// The agent has since been raised, but the plan was made at automate-safe
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.Autonomy = corev1alpha1.AutonomyDestructive
+
+	plan, err := r.loadPlan(ctx, agent, "fixer-plan1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &concurrencyTracker{}
+	eng := engine.NewEngine(agent.Name, planGuardrails(agent, plan), planTestActions, nil)
+	run := &corev1alpha1.LegatorRun{ObjectMeta: metav1.ObjectMeta{Name: "fixer-apply1", Namespace: "agents"}}
+
+	result := r.applyPlan(ctx, plan, run, eng, RunConfig{ToolRegistry: toolRegistry(planTestTools(tracker)...)}, agent, nil)
+
+	if result.phase != corev1alpha1.RunPhaseSucceeded {
+		t.Fatalf("expected Succeeded, got %s: %s", result.phase, result.report)
+	}
+	if len(result.actions) != 1 || result.actions[0].Status != corev1alpha1.ActionStatusExecuted || result.actions[0].Tool != "kubectl.rollout" {
+		t.Errorf("expected the planned rollout to execute, got %+v", result.actions)
+	}
+	if len(tracker.seen) != 1 {
+		t.Errorf("expected one tool execution, got %d", len(tracker.seen))
+	}
+
+	if _, err := r.loadPlan(ctx, agent, "fixer-plan1"); err == nil || !strings.Contains(err.Error(), "already applied by fixer-apply1") {
+		t.Errorf("expected the plan to be marked applied, got %v", err)
+	}
+}
+
+func TestApplyPlan_HonoursLoweredAutonomy(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, planTestRun())
+	r := NewRunner(c, nil, logr.Discard())
+
+	// The agent has since been turned back down to observe
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.Autonomy = corev1alpha1.AutonomyObserve
+
+	plan, err := r.loadPlan(ctx, agent, "fixer-plan1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	guardrails := planGuardrails(agent, plan)
+	if guardrails.Autonomy != corev1alpha1.AutonomyObserve {
+		t.Fatalf("expected the current autonomy to cap the plan, got %s", guardrails.Autonomy)
+	}
+	tracker := &concurrencyTracker{}
+	eng := engine.NewEngine(agent.Name, guardrails, planTestActions, nil)
+	run := &corev1alpha1.LegatorRun{ObjectMeta: metav1.ObjectMeta{Name: "fixer-apply1", Namespace: "agents"}}
+
+	result := r.applyPlan(ctx, plan, run, eng, RunConfig{ToolRegistry: toolRegistry(planTestTools(tracker)...)}, agent, nil)
+
+	if result.phase != corev1alpha1.RunPhaseBlocked || len(tracker.seen) != 0 {
+		t.Errorf("expected the rollout to be blocked, got %s with %d executions", result.phase, len(tracker.seen))
+	}
+}
+
+func TestApplyPlan_StopsWhenBlocked(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, planTestRun(corev1alpha1.ActionRecord{
+		Seq: 3, Tool: "kubectl.rollout", Target: "deployment web", Status: corev1alpha1.ActionStatusPlanned,
+		Args: `{"action":"restart","resource":"deployment","name":"web"}`,
+	}))
+	r := NewRunner(c, nil, logr.Discard())
+
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.DeniedActions = []string{"kubectl.rollout deployment api"}
+	plan, err := r.loadPlan(ctx, agent, "fixer-plan1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &concurrencyTracker{}
+	eng := engine.NewEngine(agent.Name, planGuardrails(agent, plan), planTestActions, nil)
+	run := &corev1alpha1.LegatorRun{ObjectMeta: metav1.ObjectMeta{Name: "fixer-apply1", Namespace: "agents"}}
+
+	result := r.applyPlan(ctx, plan, run, eng, RunConfig{ToolRegistry: toolRegistry(planTestTools(tracker)...)}, agent, nil)
+
+	if result.phase != corev1alpha1.RunPhaseBlocked {
+		t.Errorf("expected Blocked, got %s", result.phase)
+	}
+	if len(tracker.seen) != 0 || len(result.actions) != 1 {
+		t.Errorf("expected the apply to stop at the blocked action, %d executed, %d recorded", len(tracker.seen), len(result.actions))
+	}
+}
+
+func TestApplyPlan_RequestsOneApproval(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, planTestRun(corev1alpha1.ActionRecord{
+		Seq: 3, Tool: "kubectl.rollout", Target: "deployment web", Status: corev1alpha1.ActionStatusPlanned,
+		Args: `{"action":"restart","resource":"deployment","name":"web"}`,
+	}))
+	r := NewRunner(c, nil, logr.Discard())
+
+	// Both restarts need approval at recommend
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.Autonomy = corev1alpha1.AutonomyRecommend
+	agent.Spec.Guardrails.ApprovalMode = "mutation-gate"
+	plan, err := r.loadPlan(ctx, agent, "fixer-plan1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &concurrencyTracker{}
+	eng := engine.NewEngine(agent.Name, planGuardrails(agent, plan), planTestActions, nil)
+	run := &corev1alpha1.LegatorRun{ObjectMeta: metav1.ObjectMeta{Name: "fixer-apply1", Namespace: "agents"}}
+
+	// Approve the plan's request once it appears
+	go func() {
+		list := &corev1alpha1.ApprovalRequestList{}
+		for len(list.Items) == 0 {
+			time.Sleep(50 * time.Millisecond)
+			if err := c.List(ctx, list); err != nil {
+				t.Errorf("list approvals: %v", err)
+				return
+			}
+		}
+		ar := &list.Items[0]
+		ar.Status.Phase = corev1alpha1.ApprovalPhaseApproved
+		ar.Status.DecidedBy = "alice"
+		if err := c.Update(ctx, ar); err != nil {
+			t.Errorf("approve: %v", err)
+		}
+	}()
+
+	result := r.applyPlan(ctx, plan, run, eng, RunConfig{
+		ToolRegistry:    toolRegistry(planTestTools(tracker)...),
+		ApprovalManager: approval.NewManager(c, logr.Discard()),
+	}, agent, nil)
+
+	if result.phase != corev1alpha1.RunPhaseSucceeded {
+		t.Fatalf("expected Succeeded, got %s: %s", result.phase, result.report)
+	}
+	if len(result.actions) != 2 || result.actions[0].Status != corev1alpha1.ActionStatusApproved ||
+		result.actions[1].Status != corev1alpha1.ActionStatusApproved {
+		t.Errorf("expected both restarts to run approved, got %+v", result.actions)
+	}
+
+	requests := &corev1alpha1.ApprovalRequestList{}
+	if err := c.List(ctx, requests); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests.Items) != 1 || len(requests.Items[0].Spec.Action.Args) != 2 {
+		t.Errorf("expected one approval request covering both actions, got %+v", requests.Items)
+	}
+}
+
+func TestLoadPlan_Rejects(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		mutate func(*corev1alpha1.LegatorRun)
+		want   string
+	}{
+		{"other agent", func(p *corev1alpha1.LegatorRun) { p.Spec.AgentRef = "other" }, "belongs to agent"},
+		{"not a plan", func(p *corev1alpha1.LegatorRun) { p.Spec.Mode = "" }, "not a Plan-mode run"},
+		{"still running", func(p *corev1alpha1.LegatorRun) { p.Status.Phase = corev1alpha1.RunPhaseRunning }, "not Succeeded"},
+		{"missing args", func(p *corev1alpha1.LegatorRun) { p.Status.Actions[1].Args = "" }, "no recorded arguments"},
+		{"nothing planned", func(p *corev1alpha1.LegatorRun) { p.Status.Actions = p.Status.Actions[:1] }, "no planned actions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planTestRun()
+			tt.mutate(plan)
+			r := NewRunner(newTestClient(t, plan), nil, logr.Discard())
+			if _, err := r.loadPlan(ctx, planTestAgent(""), plan.Name); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
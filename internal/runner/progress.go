@@ -0,0 +1,118 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+const (
+	// progressBatchSize is how many actions may accumulate before a mid-iteration flush.
+	progressBatchSize = 5
+
+	// progressFlushInterval is the longest a pending action waits before being flushed.
+	progressFlushInterval = 2 * time.Second
+)
+
+// progressRecorder persists in-flight progress (actions, guardrail decisions,
+// iteration and token counters) to the LegatorRun status while the run executes,
+// so a long run is observable before finalizeRun writes the terminal state.
+//
+// Changes are batched into a single status merge patch: pending actions are
+// flushed once progressBatchSize accumulate or progressFlushInterval elapses,
+// and anything still pending is flushed at the end of each iteration (before
+// the next, potentially slow, LLM call). Patch failures are logged and retried
+// on the next flush — progress reporting never fails a run.
+//
+// A nil *progressRecorder is valid and records nothing.
+type progressRecorder struct {
+	client client.Client
+	run    *corev1alpha1.LegatorRun
+	log    logr.Logger
+
+	pending   int
+	lastFlush time.Time
+}
+
+// newProgressRecorder creates a recorder that patches the given run's status.
+func newProgressRecorder(c client.Client, run *corev1alpha1.LegatorRun, log logr.Logger) *progressRecorder {
+	return &progressRecorder{
+		client:    c,
+		run:       run,
+		log:       log,
+		lastFlush: time.Now(),
+	}
+}
+
+// runName returns the name of the run being recorded, or "" for a nil recorder.
+func (p *progressRecorder) runName() string {
+	if p == nil {
+		return ""
+	}
+	return p.run.Name
+}
+
+// actionRecorded notes that an action was appended to the result and flushes
+// if the batch is full or the flush interval has elapsed.
+func (p *progressRecorder) actionRecorded(ctx context.Context, result *conversationResult) {
+	if p == nil {
+		return
+	}
+	p.pending++
+	if p.pending >= progressBatchSize || time.Since(p.lastFlush) >= progressFlushInterval {
+		p.flush(ctx, result)
+	}
+}
+
+// iterationComplete flushes the progress of a finished iteration.
+func (p *progressRecorder) iterationComplete(ctx context.Context, result *conversationResult) {
+	if p == nil {
+		return
+	}
+	p.pending++
+	p.flush(ctx, result)
+}
+
+// flush writes the current result snapshot to the run status as a merge patch.
+func (p *progressRecorder) flush(ctx context.Context, result *conversationResult) {
+	base := p.run.DeepCopy()
+
+	p.run.Status.Actions = append([]corev1alpha1.ActionRecord(nil), result.actions...)
+	p.run.Status.Compactions = append([]corev1alpha1.CompactionEvent(nil), result.compactions...)
+	guardrails := result.guardrails
+	p.run.Status.Guardrails = &guardrails
+	p.run.Status.Usage = &corev1alpha1.UsageSummary{
+		TokensIn:      result.totalIn,
+		TokensOut:     result.totalOut,
+		TotalTokens:   result.totalIn + result.totalOut,
+		Iterations:    result.iterations,
+		EstimatedCost: result.estimatedCost(),
+	}
+
+	if err := p.client.Status().Patch(ctx, p.run, client.MergeFrom(base)); err != nil {
+		p.log.Error(err, "failed to patch LegatorRun progress",
+			"agentRun", p.run.Name,
+			"actions", len(result.actions),
+		)
+		// Roll back so the next flush diffs against what the API server has.
+		base.DeepCopyInto(p.run)
+		return
+	}
+
+	p.pending = 0
+	p.lastFlush = time.Now()
+}
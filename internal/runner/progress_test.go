@@ -0,0 +1,107 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+func progressTestRun() *corev1alpha1.LegatorRun {
+	return &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman-abc12", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman", Trigger: corev1alpha1.RunTriggerManual},
+		Status:     corev1alpha1.LegatorRunStatus{Phase: corev1alpha1.RunPhaseRunning},
+	}
+}
+
+func persistedRun(t *testing.T, c client.Client, run *corev1alpha1.LegatorRun) *corev1alpha1.LegatorRun {
+	t.Helper()
+	got := &corev1alpha1.LegatorRun{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(run), got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestProgressRecorder_BatchesActions(t *testing.T) {
+	run := progressTestRun()
+	c := newTestClient(t, run)
+	p := newProgressRecorder(c, run, logr.Discard())
+	ctx := context.Background()
+
+	result := &conversationResult{}
+	for i := 1; i < progressBatchSize; i++ {
+		result.actions = append(result.actions, corev1alpha1.ActionRecord{
+			Seq: int32(i), Tool: "kubectl.get", Status: corev1alpha1.ActionStatusExecuted,
+		})
+		p.actionRecorded(ctx, result)
+	}
+	if got := persistedRun(t, c, run); len(got.Status.Actions) != 0 {
+		t.Fatalf("expected no flush below batch size, got %d actions persisted", len(got.Status.Actions))
+	}
+
+	result.actions = append(result.actions, corev1alpha1.ActionRecord{
+		Seq: int32(progressBatchSize), Tool: "kubectl.get", Status: corev1alpha1.ActionStatusExecuted,
+	})
+	result.guardrails.ChecksPerformed = int32(progressBatchSize)
+	p.actionRecorded(ctx, result)
+
+	got := persistedRun(t, c, run)
+	if len(got.Status.Actions) != progressBatchSize {
+		t.Fatalf("expected %d actions after full batch, got %d", progressBatchSize, len(got.Status.Actions))
+	}
+	if got.Status.Guardrails == nil || got.Status.Guardrails.ChecksPerformed != int32(progressBatchSize) {
+		t.Errorf("guardrail summary not persisted: %+v", got.Status.Guardrails)
+	}
+	if got.Status.Phase != corev1alpha1.RunPhaseRunning {
+		t.Errorf("phase = %s, want Running", got.Status.Phase)
+	}
+}
+
+func TestProgressRecorder_IterationFlushes(t *testing.T) {
+	run := progressTestRun()
+	c := newTestClient(t, run)
+	p := newProgressRecorder(c, run, logr.Discard())
+	ctx := context.Background()
+
+	result := &conversationResult{iterations: 2, totalIn: 1200, totalOut: 300}
+	result.actions = append(result.actions, corev1alpha1.ActionRecord{
+		Seq: 1, Tool: "kubectl.delete", Status: corev1alpha1.ActionStatusBlocked,
+	})
+	p.actionRecorded(ctx, result)
+	p.iterationComplete(ctx, result)
+
+	got := persistedRun(t, c, run)
+	if len(got.Status.Actions) != 1 || got.Status.Actions[0].Status != corev1alpha1.ActionStatusBlocked {
+		t.Fatalf("unexpected persisted actions: %+v", got.Status.Actions)
+	}
+	if got.Status.Usage == nil || got.Status.Usage.Iterations != 2 || got.Status.Usage.TotalTokens != 1500 {
+		t.Errorf("unexpected persisted usage: %+v", got.Status.Usage)
+	}
+
+	// The in-memory run stays current with the persisted object.
+	if run.ResourceVersion != got.ResourceVersion {
+		t.Errorf("resourceVersion = %s, want %s", run.ResourceVersion, got.ResourceVersion)
+	}
+}
+
+func TestProgressRecorder_NilSafe(t *testing.T) {
+	var p *progressRecorder
+	p.actionRecorded(context.Background(), &conversationResult{})
+	p.iterationComplete(context.Background(), &conversationResult{})
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/security"
+)
+
+// newRedactor builds the redactor for content a run sends to its LLM
+// provider: the built-in secret patterns, the environment's extra patterns,
+// and the values of its resolved credentials.
+func newRedactor(env *resolver.ResolvedEnvironment) (*security.Redactor, error) {
+	if env == nil {
+		return security.NewRedactor(nil, nil)
+	}
+	return security.NewRedactor(env.RedactionPatterns, security.CredentialValues(env.Credentials))
+}
+
+// redact scrubs provider-bound text, counting redactions in the run's
+// guardrail summary.
+func (c *conversationResult) redact(redactor *security.Redactor, text string) string {
+	redacted, n := redactor.Redact(text)
+	c.guardrails.Redactions += int32(n)
+	return redacted
+}
+
+// redactToolResults scrubs tool results before they are fed back to the
+// model. The audit trail keeps its own sanitized copy of each result.
+func (c *conversationResult) redactToolResults(redactor *security.Redactor, results []provider.ToolResult) {
+	for i := range results {
+		results[i].Content = c.redact(redactor, results[i].Content)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// readSecrets is a model that reads the secrets once.
+func readSecrets() *provider.MockProvider {
+	return provider.NewMockProviderWithToolCalls([]provider.ToolCall{
+		{ID: "c1", Name: "kubectl.get", Args: map[string]interface{}{"resource": "secrets"}},
+	}, "done")
+}
+
+func TestConversationLoop_RedactsProviderBoundContent(t *testing.T) {
+	env := &resolver.ResolvedEnvironment{
+		Name:              "prod",
+		Credentials:       map[string]map[string]string{"orders-db": {"username": "orders", "password": "Xk9#mQ2!vL"}},
+		RedactionPatterns: []string{`(customer_id=)\d+`},
+	}
+	output := "DATABASE_URL=postgres://orders:Xk9#mQ2!vL@db:5432/orders\ncustomer_id=48151623\nreplicas: 3"
+
+	mock := readSecrets()
+	result := (&loopTest{
+		cfg:       RunConfig{Provider: mock},
+		tools:     []tools.Tool{&scriptedTool{name: "kubectl.get", output: output, calls: &[]string{}}},
+		assembled: &assembler.AssembledAgent{Prompt: "Check orders-db, credential Xk9#mQ2!vL.", Environment: env},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseSucceeded {
+		t.Fatalf("expected Succeeded, got %s: %s", result.phase, result.report)
+	}
+	calls := mock.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 LLM calls, got %d", len(calls))
+	}
+	if strings.Contains(calls[0].SystemPrompt, "Xk9#mQ2!vL") {
+		t.Errorf("credential reached the provider in the system prompt: %s", calls[0].SystemPrompt)
+	}
+
+	msgs := calls[1].Messages
+	sent := msgs[len(msgs)-1].ToolResults[0].Content
+	if strings.Contains(sent, "Xk9#mQ2!vL") || strings.Contains(sent, "48151623") {
+		t.Errorf("secret reached the provider in a tool result: %s", sent)
+	}
+	if !strings.Contains(sent, "customer_id=[REDACTED]") || !strings.Contains(sent, "replicas: 3") {
+		t.Errorf("unexpected redacted tool result: %s", sent)
+	}
+	// The prompt's credential, the DSN's password and the customer ID
+	if result.guardrails.Redactions != 3 {
+		t.Errorf("Redactions = %d, want 3", result.guardrails.Redactions)
+	}
+}
+
+func TestConversationLoop_RedactsWithoutEnvironment(t *testing.T) {
+	mock := readSecrets()
+	result := (&loopTest{
+		cfg:       RunConfig{Provider: mock},
+		tools:     []tools.Tool{&scriptedTool{name: "kubectl.get", output: "Authorization: Bearer abc.def.ghi", calls: &[]string{}}},
+		assembled: &assembler.AssembledAgent{Prompt: "Check the cluster."},
+	}).run()
+
+	msgs := mock.Calls()[1].Messages
+	if sent := msgs[len(msgs)-1].ToolResults[0].Content; strings.Contains(sent, "abc.def.ghi") {
+		t.Errorf("bearer token reached the provider: %s", sent)
+	}
+	if result.guardrails.Redactions == 0 {
+		t.Error("expected the redaction to be counted")
+	}
+}
+
+func TestConversationLoop_InvalidRedactionPattern(t *testing.T) {
+	env := &resolver.ResolvedEnvironment{Name: "prod", RedactionPatterns: []string{`(unclosed`}}
+
+	mock := readSecrets()
+	result := (&loopTest{
+		cfg:       RunConfig{Provider: mock},
+		tools:     []tools.Tool{&scriptedTool{name: "kubectl.get", output: "ok", calls: &[]string{}}},
+		assembled: &assembler.AssembledAgent{Prompt: "Check the cluster.", Environment: env},
+	}).run()
+
+	if result.phase != corev1alpha1.RunPhaseFailed || !strings.Contains(result.report, "invalid redaction pattern") {
+		t.Errorf("expected the run to fail on an invalid pattern, got %s: %s", result.phase, result.report)
+	}
+	if mock.CallCount() != 0 {
+		t.Errorf("expected no LLM calls, got %d", mock.CallCount())
+	}
+}
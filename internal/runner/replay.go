@@ -0,0 +1,46 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/transcript"
+)
+
+// Replay re-drives a recorded run through the conversation loop under the
+// agent's current guardrails. The model's turns are played back from the
+// transcript and tools answer with their recorded output, so each call is
+// evaluated, precondition-checked and gated exactly as a live run would be,
+// without calling the model or touching infrastructure. It returns each
+// recorded action compared with its replay.
+func (r *Runner) Replay(
+	ctx context.Context,
+	t *transcript.Transcript,
+	run *corev1alpha1.LegatorRun,
+	agent *corev1alpha1.LegatorAgent,
+	assembled *assembler.AssembledAgent,
+) []transcript.Step {
+	reg := transcript.RecordedTools(t)
+	cfg := RunConfig{
+		Provider:       transcript.NewPlaybackProvider(t),
+		ToolRegistry:   reg,
+		TriggerContext: run.Spec.TriggerContext,
+		Plan:           run.Spec.Mode == corev1alpha1.RunModePlan,
+	}
+	eng := r.newEngine(agent, &agent.Spec.Guardrails, assembled, reg)
+
+	rec := transcript.NewRecorder(agent.Name, run.Name, t.Model, assembled.Prompt)
+	r.conversationLoop(ctx, assembled, eng, cfg, agent, nil, rec)
+	return transcript.Compare(t, rec.Transcript())
+}
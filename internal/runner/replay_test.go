@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/security"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/transcript"
+)
+
+// replayTestTranscript records a fixer that read a pod, found it crashing
+// and restarted its deployment.
+func replayTestTranscript(podStatus string) *transcript.Transcript {
+	screened := func(tool, output string) string {
+		block, _ := security.ScreenToolOutput(tool, output)
+		return block
+	}
+	pod := map[string]interface{}{"resource": "pod", "name": "api"}
+	deployment := map[string]interface{}{"resource": "deployment", "name": "api"}
+	return &transcript.Transcript{
+		Version: transcript.Version,
+		Agent:   "fixer",
+		Messages: []transcript.Message{
+			{Role: "assistant", ToolCalls: []transcript.ToolCall{
+				{ID: "c1", Name: "kubectl.get", Args: pod, Seq: 1, Status: corev1alpha1.ActionStatusExecuted},
+			}},
+			{Role: "user", ToolResults: []transcript.ToolResult{{ToolCallID: "c1", Content: screened("kubectl.get", podStatus)}}},
+			{Role: "assistant", ToolCalls: []transcript.ToolCall{
+				{ID: "c2", Name: "kubectl.rollout", Args: deployment, Seq: 2, Status: corev1alpha1.ActionStatusExecuted},
+			}},
+			{Role: "user", ToolResults: []transcript.ToolResult{{ToolCallID: "c2", Content: screened("kubectl.rollout", "restarted")}}},
+			{Role: "assistant", Content: "Restarted api."},
+		},
+	}
+}
+
+func replay(t *testing.T, tr *transcript.Transcript, autonomy corev1alpha1.AutonomyLevel) []transcript.Step {
+	t.Helper()
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.Autonomy = autonomy
+	assembled := &assembler.AssembledAgent{
+		Prompt:      "Fix the cluster.",
+		Environment: &resolver.ResolvedEnvironment{},
+		Model:       &resolver.ResolvedModel{Provider: "anthropic", Model: "claude-sonnet-4", FullModelString: "anthropic/claude-sonnet-4"},
+		ActionRegistry: map[string]*skill.Action{
+			"restart": {ID: "restart", Tool: "kubectl.rollout", Tier: "service-mutation",
+				PreConditions: []skill.PreCondition{{
+					Check:      `kubectl.get resource=pod name=${name} contains "CrashLoopBackOff"`,
+					FailAction: skill.FailActionSkip,
+				}}},
+		},
+	}
+	run := &corev1alpha1.LegatorRun{ObjectMeta: metav1.ObjectMeta{Name: "fixer-abc12", Namespace: "agents"}}
+
+	steps := NewRunner(nil, nil, logr.Discard()).Replay(context.Background(), tr, run, agent, assembled)
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %+v", steps)
+	}
+	if steps[0].Changed() {
+		t.Errorf("the read should be unchanged, got %s", steps[0].Replayed)
+	}
+	return steps
+}
+
+func TestReplay_SameGuardrails(t *testing.T) {
+	steps := replay(t, replayTestTranscript("api 0/1 CrashLoopBackOff"), corev1alpha1.AutonomySafe)
+	if steps[1].Changed() {
+		t.Errorf("the restart changed under the original guardrails: %s -> %s (%s)",
+			steps[1].Recorded, steps[1].Replayed, steps[1].Reason)
+	}
+}
+
+func TestReplay_StricterGuardrails(t *testing.T) {
+	steps := replay(t, replayTestTranscript("api 0/1 CrashLoopBackOff"), corev1alpha1.AutonomyObserve)
+	if steps[1].Replayed != transcript.OutcomeBlocked || steps[1].Reason == "" {
+		t.Errorf("expected the restart to be blocked with a reason, got %+v", steps[1])
+	}
+}
+
+func TestReplay_SkippedPreCondition(t *testing.T) {
+	// The precondition reads the recorded pod status, which no longer
+	// calls for a restart.
+	steps := replay(t, replayTestTranscript("api 1/1 Running"), corev1alpha1.AutonomySafe)
+	if steps[1].Replayed != transcript.OutcomeSkipped || steps[1].ReplayedStatus != corev1alpha1.ActionStatusSkipped {
+		t.Errorf("expected the restart to be skipped, not blocked, got %+v", steps[1])
+	}
+}
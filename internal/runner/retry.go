@@ -0,0 +1,68 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"time"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// Retry backoff: the first retry waits retryBaseDelay, doubling on each
+// retry up to retryMaxDelay. Variables so tests can shorten them.
+var (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 8 * time.Second
+)
+
+// retryLimit is how many times a tool call may be retried: the agent's
+// maxRetries for reads and for mutations whose action is declared
+// idempotent, and never for other mutations.
+func retryLimit(agent *corev1alpha1.LegatorAgent, decision *engine.Decision) int32 {
+	if decision.Tier == corev1alpha1.ActionTierRead ||
+		(decision.MatchedAction != nil && decision.MatchedAction.Idempotent) {
+		return max(agent.Spec.Guardrails.MaxRetries, 0)
+	}
+	return 0
+}
+
+// executeWithRetry runs a tool, retrying up to limit times with backoff
+// while the tool reports the failure as transient. Tools that don't
+// implement tools.RetryableTool are run once. It returns the last attempt's
+// output and error, and how many retries were made.
+func executeWithRetry(ctx context.Context, reg *tools.Registry, name string, args map[string]interface{}, limit int32) (string, int32, error) {
+	output, err := reg.Execute(ctx, name, args)
+	if limit <= 0 {
+		return output, 0, err
+	}
+	tool, _ := reg.Get(name)
+	rt, ok := tool.(tools.RetryableTool)
+	if !ok {
+		return output, 0, err
+	}
+
+	delay := retryBaseDelay
+	var retries int32
+	for retries < limit && rt.Retryable(output, err) {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return output, retries, err
+		}
+		delay = min(delay*2, retryMaxDelay)
+		retries++
+		output, err = reg.Execute(ctx, name, args)
+	}
+	return output, retries, err
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// flakyTool fails with a transient error until it has been called
+// failures+1 times.
+type flakyTool struct {
+	name     string
+	failures int
+	calls    int
+}
+
+func (t *flakyTool) Name() string                       { return t.name }
+func (t *flakyTool) Description() string                { return t.name }
+func (t *flakyTool) Parameters() map[string]interface{} { return nil }
+
+func (t *flakyTool) Execute(context.Context, map[string]interface{}) (string, error) {
+	t.calls++
+	if t.calls <= t.failures {
+		return "", errors.New("dial tcp 10.0.0.1:443: i/o timeout")
+	}
+	return "ok", nil
+}
+
+func (t *flakyTool) Retryable(_ string, err error) bool { return tools.IsTransientError(err) }
+
+func shortRetryDelays(t *testing.T) {
+	t.Helper()
+	base, maxDelay := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 2*time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = base, maxDelay })
+}
+
+func TestExecuteWithRetry(t *testing.T) {
+	shortRetryDelays(t)
+	ctx := context.Background()
+
+	flaky := &flakyTool{name: "kubectl.get", failures: 2}
+	reg := tools.NewRegistry()
+	reg.Register(flaky)
+	output, retries, err := executeWithRetry(ctx, reg, "kubectl.get", nil, 2)
+	if err != nil || output != "ok" || retries != 2 || flaky.calls != 3 {
+		t.Errorf("expected success after 2 retries, got %q, %d retries, %d calls, err %v", output, retries, flaky.calls, err)
+	}
+
+	flaky = &flakyTool{name: "kubectl.get", failures: 5}
+	reg.Register(flaky)
+	if _, retries, err := executeWithRetry(ctx, reg, "kubectl.get", nil, 2); err == nil || retries != 2 || flaky.calls != 3 {
+		t.Errorf("expected the last error after 2 retries, got %d retries, %d calls, err %v", retries, flaky.calls, err)
+	}
+
+	// Tools that can't classify their errors run once
+	tracker := &concurrencyTracker{}
+	reg.Register(&trackingTool{name: "kubectl.describe", tracker: tracker})
+	if _, retries, _ := executeWithRetry(ctx, reg, "kubectl.describe", nil, 2); retries != 0 || len(tracker.seen) != 1 {
+		t.Errorf("expected a single call, got %d retries, %d calls", retries, len(tracker.seen))
+	}
+}
+
+func TestRetryLimit(t *testing.T) {
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.MaxRetries = 3
+
+	tests := []struct {
+		name     string
+		decision *engine.Decision
+		want     int32
+	}{
+		{"read", &engine.Decision{Tier: corev1alpha1.ActionTierRead}, 3},
+		{"mutation", &engine.Decision{Tier: corev1alpha1.ActionTierServiceMutation, MatchedAction: &skill.Action{ID: "restart"}}, 0},
+		{"idempotent mutation", &engine.Decision{Tier: corev1alpha1.ActionTierServiceMutation, MatchedAction: &skill.Action{ID: "scale", Idempotent: true}}, 3},
+	}
+	for _, tt := range tests {
+		if got := retryLimit(agent, tt.decision); got != tt.want {
+			t.Errorf("%s: retryLimit = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConversationLoop_NeverRetriesMutations(t *testing.T) {
+	shortRetryDelays(t)
+	read := &flakyTool{name: "kubectl.get", failures: 1}
+	mutation := &flakyTool{name: "kubectl.rollout", failures: 1}
+
+	mock := provider.NewMockProvider([]*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{
+			{ID: "c1", Name: "kubectl.get", Args: map[string]interface{}{"resource": "deployment", "name": "api"}},
+			{ID: "c2", Name: "kubectl.rollout", Args: map[string]interface{}{"action": "restart", "resource": "deployment", "name": "api"}},
+		}},
+		{Content: "done"},
+	}, []error{nil, nil})
+
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.MaxRetries = 2
+	result := (&loopTest{
+		cfg:     RunConfig{Provider: mock},
+		tools:   []tools.Tool{read, mutation},
+		agent:   agent,
+		actions: planTestActions,
+	}).run()
+
+	if read.calls != 2 || result.actions[0].Status != corev1alpha1.ActionStatusExecuted || result.actions[0].Retries != 1 {
+		t.Errorf("the read should succeed on retry, got %d calls, %+v", read.calls, result.actions[0])
+	}
+	if mutation.calls != 1 || result.actions[1].Status != corev1alpha1.ActionStatusFailed || result.actions[1].Retries != 0 {
+		t.Errorf("the mutation should not be retried, got %d calls, %+v", mutation.calls, result.actions[1])
+	}
+}
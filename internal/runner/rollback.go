@@ -0,0 +1,275 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/approval"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// rollbackGrace is added to the approval timeout when rolling back a failed
+// run, whose own deadline may already have passed.
+const rollbackGrace = time.Minute
+
+// rollbackHandle is an executed mutation that declared a rollback command.
+type rollbackHandle struct {
+	seq    int32
+	action *skill.Action
+	args   map[string]interface{}
+	target string
+	done   bool
+}
+
+// trackRollback records a rollback handle for an executed mutation whose
+// action declares a rollback command.
+func (c *conversationResult) trackRollback(record *corev1alpha1.ActionRecord, tc provider.ToolCall, decision *engine.Decision) *rollbackHandle {
+	a := decision.MatchedAction
+	if a == nil || a.Rollback == nil || a.Rollback.Command == "" || record.Tier == corev1alpha1.ActionTierRead {
+		return nil
+	}
+	h := &rollbackHandle{seq: record.Seq, action: a, args: tc.Args, target: record.Target}
+	c.rollbacks = append(c.rollbacks, h)
+	return h
+}
+
+// nextSeq is the seq for an action recorded outside the model's tool calls.
+func (c *conversationResult) nextSeq() int32 {
+	if len(c.actions) == 0 {
+		return 1
+	}
+	return c.actions[len(c.actions)-1].Seq + 1
+}
+
+// postCheck runs an executed mutation's post-check, if its action declares
+// one. It returns why the check failed, or "" if it passed.
+func postCheck(ctx context.Context, eng *engine.Engine, cfg RunConfig, agent *corev1alpha1.LegatorAgent, result *conversationResult, tc provider.ToolCall, decision *engine.Decision) string {
+	a := decision.MatchedAction
+	if a == nil || a.PostCheck == nil {
+		return ""
+	}
+	check := a.PostCheck
+
+	if d, err := time.ParseDuration(check.Delay); err == nil && d > 0 {
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return fmt.Sprintf("%s not run: %v", check.Tool, ctx.Err())
+		}
+	}
+
+	args := skill.ExpandArgs(check.Args, tc.Args)
+	target := tools.ExtractTarget(check.Tool, args)
+	checkDecision := eng.Evaluate(check.Tool, target)
+	result.guardrails.ChecksPerformed++
+	if !checkDecision.Allowed || checkDecision.Tier != corev1alpha1.ActionTierRead {
+		return fmt.Sprintf("%s on %s is not an allowed read: %s", check.Tool, target, checkDecision.BlockReason)
+	}
+
+	output, _, err := executeWithRetry(ctx, cfg.ToolRegistry, check.Tool, args, retryLimit(agent, checkDecision))
+	if err != nil {
+		return fmt.Sprintf("%s on %s: %v", check.Tool, target, err)
+	}
+	if check.Expect != "" && !strings.Contains(output, skill.Expand(check.Expect, tc.Args)) {
+		return fmt.Sprintf("%s on %s: output does not contain %q", check.Tool, target, check.Expect)
+	}
+	return ""
+}
+
+// verifyMutation runs after a mutation executes successfully. It tracks the
+// action's rollback and runs its post-check, rolling the action back if the
+// check fails. The returned note is appended to the model's tool result.
+// The record must already be in result.actions.
+func (r *Runner) verifyMutation(
+	ctx context.Context,
+	tc provider.ToolCall,
+	decision *engine.Decision,
+	eng *engine.Engine,
+	cfg RunConfig,
+	agent *corev1alpha1.LegatorAgent,
+	result *conversationResult,
+	progress *progressRecorder,
+) string {
+	record := &result.actions[len(result.actions)-1]
+	h := result.trackRollback(record, tc, decision)
+
+	failure := postCheck(ctx, eng, cfg, agent, result, tc, decision)
+	if failure == "" {
+		return ""
+	}
+	record.Result += "\n\npost-check failed: " + failure
+	r.log.Info("post-check failed",
+		"agent", agent.Name,
+		"tool", tc.Name,
+		"target", record.Target,
+		"reason", failure,
+	)
+
+	note := "\n\nPOST-CHECK FAILED: " + failure
+	if h == nil {
+		return note + ". No rollback is declared for this action."
+	}
+	rb := r.rollback(ctx, h, "post-check failed: "+failure, eng, cfg, agent, result, progress)
+	return note + fmt.Sprintf(". Rollback %s: %s", rb.Status, rb.Result)
+}
+
+// rollbackRun rolls back a failed run's executed mutations, newest first.
+func (r *Runner) rollbackRun(
+	ctx context.Context,
+	eng *engine.Engine,
+	cfg RunConfig,
+	agent *corev1alpha1.LegatorAgent,
+	result *conversationResult,
+	progress *progressRecorder,
+) {
+	if result.phase != corev1alpha1.RunPhaseFailed {
+		return
+	}
+	var pending bool
+	for _, h := range result.rollbacks {
+		pending = pending || !h.done
+	}
+	if !pending {
+		return
+	}
+
+	// The run's deadline may have passed, and an approval may take up to
+	// its timeout
+	timeout := 30 * time.Minute
+	if d, err := time.ParseDuration(agent.Spec.Guardrails.ApprovalTimeout); err == nil {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout+rollbackGrace)
+	defer cancel()
+
+	for i := len(result.rollbacks) - 1; i >= 0; i-- {
+		if h := result.rollbacks[i]; !h.done {
+			r.rollback(ctx, h, "run failed: "+result.report, eng, cfg, agent, result, progress)
+		}
+	}
+}
+
+// rollback runs a rollback command, directly if the action's rollback is
+// automatic and otherwise once approved, and records it as an action
+// linked to the one it undoes. The command is evaluated by the engine like
+// any other action.
+func (r *Runner) rollback(
+	ctx context.Context,
+	h *rollbackHandle,
+	reason string,
+	eng *engine.Engine,
+	cfg RunConfig,
+	agent *corev1alpha1.LegatorAgent,
+	result *conversationResult,
+	progress *progressRecorder,
+) corev1alpha1.ActionRecord {
+	h.done = true
+	spec := h.action.Rollback
+
+	record := corev1alpha1.ActionRecord{
+		Seq:        result.nextSeq(),
+		Timestamp:  metav1.Now(),
+		RollbackOf: h.seq,
+	}
+	defer func() {
+		result.actions = append(result.actions, record)
+		progress.actionRecorded(ctx, result)
+	}()
+
+	tool, args, err := skill.ParseCommand(spec.Command, h.args)
+	if err != nil {
+		record.Tool = spec.Command
+		record.Status = corev1alpha1.ActionStatusFailed
+		record.Result = fmt.Sprintf("invalid rollback command: %v", err)
+		return record
+	}
+	record.Tool = tool
+	record.Target = tools.ExtractTarget(tool, args)
+
+	decision := eng.Evaluate(tool, record.Target)
+	result.guardrails.ChecksPerformed++
+	record.Tier = decision.Tier
+	record.PreFlightCheck = preFlightResult(decision)
+
+	r.log.Info("rolling back action",
+		"agent", agent.Name,
+		"seq", h.seq,
+		"action", h.action.ID,
+		"tool", tool,
+		"target", record.Target,
+		"reason", reason,
+	)
+
+	if !decision.Allowed && !decision.NeedsApproval {
+		record.Status = decision.Status
+		record.Result = "rollback blocked: " + decision.BlockReason
+		result.guardrails.ActionsBlocked++
+		return record
+	}
+
+	approved := false
+	if !spec.Automatic || decision.NeedsApproval {
+		if cfg.ApprovalManager == nil {
+			record.Status = corev1alpha1.ActionStatusBlocked
+			record.Result = "rollback needs approval, but no approval manager is configured"
+			result.guardrails.ActionsBlocked++
+			return record
+		}
+		description := fmt.Sprintf("Roll back %s on %s (action %d) because %s", h.action.ID, h.target, h.seq, reason)
+		if spec.Description != "" {
+			description += ". " + spec.Description
+		}
+		approvalResult, approvalErr := cfg.ApprovalManager.RequestApproval(ctx, approval.ApprovalParams{
+			AgentName:   agent.Name,
+			RunName:     progress.runName(),
+			Namespace:   agent.Namespace,
+			Tool:        tool,
+			Tier:        decision.Tier,
+			Target:      record.Target,
+			Description: description,
+			Timeout:     agent.Spec.Guardrails.ApprovalTimeout,
+		})
+		if approvalErr != nil || !approvalResult.Approved {
+			record.Status = corev1alpha1.ActionStatusBlocked
+			record.Result = fmt.Sprintf("rollback approval expired or failed: %v", approvalErr)
+			if approvalResult != nil && approvalResult.Phase == corev1alpha1.ApprovalPhaseDenied {
+				record.Status = corev1alpha1.ActionStatusDenied
+				record.Result = fmt.Sprintf("rollback denied by %s: %s", approvalResult.DecidedBy, approvalResult.Reason)
+			}
+			result.guardrails.ActionsBlocked++
+			return record
+		}
+		approved = true
+	}
+
+	output, retries, err := executeWithRetry(ctx, cfg.ToolRegistry, tool, args, retryLimit(agent, decision))
+	record.Retries = retries
+	applyToolOutput(&record, provider.ToolCall{Name: tool, Args: args}, output, err)
+	if err == nil {
+		if approved {
+			record.Status = corev1alpha1.ActionStatusApproved
+		}
+		if decision.MatchedAction != nil {
+			eng.RecordExecution(decision.MatchedAction.ID, record.Target)
+		}
+	}
+	return record
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// scriptedTool returns a fixed output and records the args of each call.
+type scriptedTool struct {
+	name   string
+	output string
+	calls  *[]string
+}
+
+func (t *scriptedTool) Name() string                       { return t.name }
+func (t *scriptedTool) Description() string                { return t.name }
+func (t *scriptedTool) Parameters() map[string]interface{} { return nil }
+
+func (t *scriptedTool) Execute(_ context.Context, args map[string]interface{}) (string, error) {
+	call := t.name + " " + tools.ExtractTarget(t.name, args)
+	if replicas, ok := args["replicas"]; ok {
+		call += fmt.Sprintf(" replicas=%v", replicas)
+	}
+	*t.calls = append(*t.calls, call)
+	return t.output, nil
+}
+
+func rollbackTestActions(automatic bool) map[string]*skill.Action {
+	return map[string]*skill.Action{
+		"scale": {
+			ID: "scale", Tool: "kubectl.scale", Tier: "service-mutation",
+			PostCheck: &skill.PostCheck{Tool: "kubectl.get", Args: map[string]string{"resource": "deployment", "name": "${name}"}, Expect: "${replicas}/${replicas}"},
+			Rollback:  &skill.RollbackSpec{Automatic: automatic, Command: "kubectl.scale resource=deployment name=${name} replicas=1"},
+		},
+	}
+}
+
+// runWithRollback runs the conversation loop, then rolls back the run as
+// finalizeRun would.
+func runWithRollback(automatic bool, getOutput string, responses []*provider.CompletionResponse, errs []error) (*conversationResult, *provider.MockProvider, []string) {
+	var calls []string
+	mock := provider.NewMockProvider(responses, errs)
+	lt := &loopTest{
+		cfg: RunConfig{Provider: mock},
+		tools: []tools.Tool{
+			&scriptedTool{name: "kubectl.get", output: getOutput, calls: &calls},
+			&scriptedTool{name: "kubectl.scale", output: "scaled", calls: &calls},
+		},
+		agent:     planTestAgent(""),
+		actions:   rollbackTestActions(automatic),
+		assembled: &assembler.AssembledAgent{Prompt: "scale things"},
+	}
+
+	result := lt.run()
+	lt.runner.rollbackRun(context.Background(), lt.eng, lt.cfg, lt.agent, result, nil)
+	return result, mock, calls
+}
+
+func scaleCall(id, name string) provider.ToolCall {
+	return provider.ToolCall{ID: id, Name: "kubectl.scale", Args: map[string]interface{}{"resource": "deployment", "name": name, "replicas": float64(3)}}
+}
+
+func TestPostCheckFailure_RollsBack(t *testing.T) {
+	result, mock, calls := runWithRollback(true, "api 1/3", []*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{scaleCall("c1", "api")}},
+		{Content: "Scaled, then rolled back."},
+	}, []error{nil, nil})
+
+	want := []string{"kubectl.scale deployment api replicas=3", "kubectl.get deployment api", "kubectl.scale deployment api replicas=1"}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Errorf("tool calls = %q, want %q", calls, want)
+	}
+	if len(result.actions) != 2 {
+		t.Fatalf("expected the action and its rollback, got %+v", result.actions)
+	}
+	if !strings.Contains(result.actions[0].Result, "post-check failed") {
+		t.Errorf("the action should record the failed post-check, got %q", result.actions[0].Result)
+	}
+	rb := result.actions[1]
+	if rb.RollbackOf != 1 || rb.Seq != 2 || rb.Status != corev1alpha1.ActionStatusExecuted {
+		t.Errorf("unexpected rollback record %+v", rb)
+	}
+	if res := mock.Calls()[1].Messages[2].ToolResults[0].Content; !strings.Contains(res, "POST-CHECK FAILED") || !strings.Contains(res, "Rollback executed") {
+		t.Errorf("the model should be told about the rollback, got %q", res)
+	}
+	if result.phase != corev1alpha1.RunPhaseSucceeded {
+		t.Errorf("a rolled back post-check should not fail the run, got %s", result.phase)
+	}
+}
+
+func TestPostCheckPass_NoRollback(t *testing.T) {
+	result, _, calls := runWithRollback(true, "api 3/3", []*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{scaleCall("c1", "api")}},
+		{Content: "Scaled."},
+	}, []error{nil, nil})
+
+	if len(calls) != 2 || len(result.actions) != 1 || len(result.rollbacks) != 1 {
+		t.Errorf("expected the scale and its check only, got calls %q, actions %+v", calls, result.actions)
+	}
+}
+
+func TestFailedRun_RollsBackNewestFirst(t *testing.T) {
+	result, _, calls := runWithRollback(true, "3/3", []*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{scaleCall("c1", "api"), scaleCall("c2", "web")}},
+		nil,
+	}, []error{nil, errors.New("provider unavailable")})
+
+	if result.phase != corev1alpha1.RunPhaseFailed {
+		t.Fatalf("expected Failed, got %s", result.phase)
+	}
+	if got := calls[len(calls)-2:]; got[0] != "kubectl.scale deployment web replicas=1" || got[1] != "kubectl.scale deployment api replicas=1" {
+		t.Errorf("expected rollbacks newest first, got %q", got)
+	}
+	if n := len(result.actions); n != 4 || result.actions[2].RollbackOf != 2 || result.actions[3].RollbackOf != 1 {
+		t.Errorf("unexpected actions %+v", result.actions)
+	}
+}
+
+func TestFailedRun_ManualRollbackNeedsApproval(t *testing.T) {
+	result, _, calls := runWithRollback(false, "3/3", []*provider.CompletionResponse{
+		{ToolCalls: []provider.ToolCall{scaleCall("c1", "api")}},
+		nil,
+	}, []error{nil, errors.New("provider unavailable")})
+
+	if len(calls) != 2 {
+		t.Errorf("the rollback should not run without approval, got calls %q", calls)
+	}
+	rb := result.actions[len(result.actions)-1]
+	if rb.RollbackOf != 1 || rb.Status != corev1alpha1.ActionStatusBlocked || !strings.Contains(rb.Result, "needs approval") {
+		t.Errorf("unexpected rollback record %+v", rb)
+	}
+}
@@ -12,11 +12,16 @@ package runner
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
 )
 
 // TestNotifyFuncCalledAfterRun verifies that NotifyFunc is called
@@ -120,3 +125,88 @@ func TestCleanupChaining(t *testing.T) {
 		t.Errorf("expected [vault notify], got %v", calls)
 	}
 }
+
+// TestCassetteReplay verifies a recorded run replays identically from its
+// cassette, without calling the model.
+func TestCassetteReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchman.cassette")
+
+	model := provider.NewMockProviderWithToolCalls([]provider.ToolCall{
+		{ID: "toolu_1", Name: "kubectl.get", Args: map[string]interface{}{"resource": "pods", "name": "api"}},
+		{ID: "toolu_2", Name: "kubectl.get", Args: map[string]interface{}{"resource": "pods", "name": "web"}},
+	}, "All pods healthy.")
+	run := func(p provider.Provider) *conversationResult {
+		return (&loopTest{
+			cfg:   RunConfig{Provider: p},
+			tools: []tools.Tool{&trackingTool{name: "kubectl.get", tracker: &concurrencyTracker{}}},
+		}).run()
+	}
+	recorded := run(provider.NewCassetteRecorder(model, path))
+
+	player, err := provider.NewCassettePlayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := run(player)
+
+	if replayed.err != nil || replayed.report != recorded.report || replayed.report != "All pods healthy." {
+		t.Fatalf("replay diverged: report %q (err %v), recorded %q", replayed.report, replayed.err, recorded.report)
+	}
+	if len(replayed.actions) != len(recorded.actions) || len(replayed.actions) != 2 {
+		t.Fatalf("expected 2 actions in both runs, got %d and %d", len(recorded.actions), len(replayed.actions))
+	}
+	for i := range recorded.actions {
+		if replayed.actions[i].Target != recorded.actions[i].Target || replayed.actions[i].Status != recorded.actions[i].Status {
+			t.Errorf("action %d diverged: %+v vs %+v", i, replayed.actions[i], recorded.actions[i])
+		}
+	}
+	if replayed.totalIn != recorded.totalIn || replayed.totalOut != recorded.totalOut {
+		t.Errorf("usage diverged: %d/%d vs %d/%d", replayed.totalIn, replayed.totalOut, recorded.totalIn, recorded.totalOut)
+	}
+	if model.CallCount() != 2 {
+		t.Errorf("expected the model to be called only while recording, got %d calls", model.CallCount())
+	}
+}
+
+// TestConversationLoop_SkippedPreCondition verifies a failAction skip
+// precondition skips the action without treating it as a violation.
+func TestConversationLoop_SkippedPreCondition(t *testing.T) {
+	calls := &[]string{}
+	agent := planTestAgent("")
+	agent.Spec.Guardrails.Escalation = &corev1alpha1.EscalationSpec{Target: corev1alpha1.EscalationHuman}
+	mock := provider.NewMockProviderWithToolCalls([]provider.ToolCall{
+		{ID: "c1", Name: "kubectl.rollout", Args: map[string]interface{}{"resource": "deployment", "name": "api"}},
+	}, "api is healthy, nothing to do.")
+
+	result := (&loopTest{
+		cfg: RunConfig{Provider: mock},
+		tools: []tools.Tool{
+			&scriptedTool{name: "kubectl.get", output: "api Running", calls: calls},
+			&scriptedTool{name: "kubectl.rollout", output: "restarted", calls: calls},
+		},
+		agent: agent,
+		actions: map[string]*skill.Action{
+			"restart": {ID: "restart", Tool: "kubectl.rollout", Tier: "service-mutation",
+				PreConditions: []skill.PreCondition{{
+					Check:      `kubectl.get resource=pod name=${name} contains "CrashLoopBackOff"`,
+					FailAction: skill.FailActionSkip,
+				}}},
+		},
+	}).run()
+
+	if len(result.actions) != 1 || result.actions[0].Status != corev1alpha1.ActionStatusSkipped {
+		t.Fatalf("expected the restart to be skipped, got %+v", result.actions)
+	}
+	if result.phase != corev1alpha1.RunPhaseSucceeded || result.guardrails.ActionsBlocked != 0 ||
+		result.guardrails.EscalationsTriggered != 0 || result.actions[0].Escalation != nil {
+		t.Errorf("a skip is not a violation: phase %s, %d blocked, %d escalations",
+			result.phase, result.guardrails.ActionsBlocked, result.guardrails.EscalationsTriggered)
+	}
+	msgs := mock.Calls()[1].Messages
+	if res := msgs[len(msgs)-1].ToolResults[0]; !strings.HasPrefix(res.Content, "SKIPPED:") || res.IsError {
+		t.Errorf("unexpected tool result %+v", res)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("expected only the precondition's read to run, got %v", *calls)
+	}
+}
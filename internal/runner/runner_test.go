@@ -11,11 +11,92 @@ You may obtain a copy of the License at
 package runner
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/engine"
+	"github.com/marcus-qen/legator/internal/resolver"
+	"github.com/marcus-qen/legator/internal/skill"
+	"github.com/marcus-qen/legator/internal/tools"
 )
 
+// loopTest is a conversation loop run for tests. cfg must set the Provider;
+// other unset fields default to a read-only agent checking the cluster.
+type loopTest struct {
+	cfg       RunConfig
+	tools     []tools.Tool
+	agent     *corev1alpha1.LegatorAgent
+	actions   map[string]*skill.Action
+	assembled *assembler.AssembledAgent
+
+	// runner and eng are set by run, for calls made after the loop.
+	runner *Runner
+	eng    *engine.Engine
+}
+
+// newTestClient returns a fake client holding objs, with status updates
+// going through the LegatorRun status subresource.
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&corev1alpha1.LegatorRun{}).
+		WithObjects(objs...).
+		Build()
+}
+
+// toolRegistry registers the tools in a new registry.
+func toolRegistry(ts ...tools.Tool) *tools.Registry {
+	reg := tools.NewRegistry()
+	for _, tool := range ts {
+		reg.Register(tool)
+	}
+	return reg
+}
+
+// run drives the conversation loop with the tools registered and returns
+// its result.
+func (lt *loopTest) run() *conversationResult {
+	if lt.agent == nil {
+		lt.agent = &corev1alpha1.LegatorAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"},
+			Spec: corev1alpha1.LegatorAgentSpec{
+				Model:      corev1alpha1.ModelSpec{TokenBudget: 100000},
+				Guardrails: corev1alpha1.GuardrailsSpec{Autonomy: corev1alpha1.AutonomyObserve, MaxIterations: 5},
+			},
+		}
+	}
+	if lt.assembled == nil {
+		lt.assembled = &assembler.AssembledAgent{Prompt: "Check the cluster."}
+	}
+	if lt.assembled.Model == nil {
+		lt.assembled.Model = &resolver.ResolvedModel{Provider: "anthropic", Model: "claude-sonnet-4", FullModelString: "anthropic/claude-sonnet-4"}
+	}
+	reg := toolRegistry(lt.tools...)
+	lt.cfg.ToolRegistry = reg
+
+	lt.eng = engine.NewEngine(lt.agent.Name, &lt.agent.Spec.Guardrails, lt.actions, nil).WithToolRegistry(reg)
+	lt.runner = NewRunner(nil, nil, logr.Discard())
+	return lt.runner.conversationLoop(context.Background(), lt.assembled, lt.eng, lt.cfg, lt.agent, nil, nil)
+}
+
 func TestExtractFindings(t *testing.T) {
 	content := `Everything looks healthy.
 
@@ -59,3 +140,23 @@ func TestExtractFindings_Empty(t *testing.T) {
 		t.Errorf("expected 0 findings, got %d", len(findings))
 	}
 }
+
+func TestInitialUserMessage_NoTrigger(t *testing.T) {
+	msg := initialUserMessage(nil)
+	if strings.Contains(msg, "trigger-payload") {
+		t.Errorf("unexpected trigger payload block: %s", msg)
+	}
+}
+
+func TestInitialUserMessage_WithPayload(t *testing.T) {
+	msg := initialUserMessage(&corev1alpha1.TriggerContext{
+		Source:  "alertmanager",
+		Payload: `{"alerts":[{"labels":{"alertname":"NodeDown"}}]}`,
+	})
+	if !strings.HasPrefix(msg, "Execute your task now.") {
+		t.Errorf("task instruction missing: %s", msg)
+	}
+	if !strings.Contains(msg, `"alertmanager"`) || !strings.Contains(msg, "NodeDown") {
+		t.Errorf("trigger context missing from message: %s", msg)
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/security"
+)
+
+// +kubebuilder:rbac:groups=legator.io,resources=agentevents,verbs=get;list;create
+// +kubebuilder:rbac:groups=legator.io,resources=agentevents/status,verbs=get;update
+
+// Alertmanager notification statuses.
+const (
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
+)
+
+const (
+	// alertmanagerSchemaVersion is the webhook payload version we understand.
+	alertmanagerSchemaVersion = "4"
+
+	// maxTriggerAlerts caps how many alerts are copied into a run's trigger context.
+	maxTriggerAlerts = 20
+
+	// maxAlertValueBytes caps each label and annotation value in the trigger context.
+	maxAlertValueBytes = 1024
+
+	// defaultAlertGroupTTL is how long a firing group is remembered. Matches
+	// Alertmanager's default repeat_interval, so a group still firing after
+	// that long starts a fresh run.
+	defaultAlertGroupTTL = 4 * time.Hour
+)
+
+// AlertmanagerPayload is the Alertmanager webhook body (schema version 4).
+type AlertmanagerPayload struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert within a notification.
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// ParseAlertmanagerPayload decodes and validates an Alertmanager webhook body.
+func ParseAlertmanagerPayload(body []byte) (*AlertmanagerPayload, error) {
+	var p AlertmanagerPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("decode alertmanager payload: %w", err)
+	}
+	if p.Version != alertmanagerSchemaVersion {
+		return nil, fmt.Errorf("unsupported alertmanager payload version %q (want %q)", p.Version, alertmanagerSchemaVersion)
+	}
+	if p.GroupKey == "" {
+		return nil, fmt.Errorf("alertmanager payload has no groupKey")
+	}
+	if p.Status != AlertStatusFiring && p.Status != AlertStatusResolved {
+		return nil, fmt.Errorf("unknown alertmanager status %q", p.Status)
+	}
+	return &p, nil
+}
+
+// firingAlerts returns the alerts in the notification that are still firing.
+func (p *AlertmanagerPayload) firingAlerts() []AlertmanagerAlert {
+	var out []AlertmanagerAlert
+	for _, a := range p.Alerts {
+		if a.Status == AlertStatusFiring {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// alertName returns the alertname shared by the group, if any.
+func (p *AlertmanagerPayload) alertName() string {
+	if name := p.CommonLabels["alertname"]; name != "" {
+		return name
+	}
+	return p.GroupLabels["alertname"]
+}
+
+// AlertTrigger is emitted when an Alertmanager notification matches an
+// agent's alertmanager trigger. Firing notifications start a run; resolved
+// notifications are published as AgentEvents.
+type AlertTrigger struct {
+	AgentKey types.NamespacedName
+	Source   string
+	Alert    *AlertmanagerPayload
+	Time     time.Time
+}
+
+// Resolved reports whether the notification resolves the group.
+func (t AlertTrigger) Resolved() bool {
+	return t.Alert.Status == AlertStatusResolved
+}
+
+// TriggerContext returns the run's trigger context: the group's common labels
+// and annotations, its firing alerts, and a sanitized JSON summary for the
+// agent's first message.
+func (t AlertTrigger) TriggerContext() *corev1alpha1.TriggerContext {
+	p := t.Alert
+	tc := &corev1alpha1.TriggerContext{
+		Source:      t.Source,
+		GroupKey:    p.GroupKey,
+		Labels:      sanitizeAlertMap(p.CommonLabels),
+		Annotations: sanitizeAlertMap(p.CommonAnnotations),
+	}
+
+	firing := p.firingAlerts()
+	if len(firing) > maxTriggerAlerts {
+		firing = firing[:maxTriggerAlerts]
+	}
+	for _, a := range firing {
+		tc.Alerts = append(tc.Alerts, corev1alpha1.TriggerAlert{
+			Fingerprint: a.Fingerprint,
+			Status:      a.Status,
+			Labels:      sanitizeAlertMap(a.Labels),
+			Annotations: sanitizeAlertMap(a.Annotations),
+		})
+	}
+
+	summary := map[string]interface{}{
+		"status":      p.Status,
+		"receiver":    p.Receiver,
+		"groupLabels": p.GroupLabels,
+		"alerts":      tc.Alerts,
+	}
+	if omitted := len(p.Alerts) - len(tc.Alerts) + p.TruncatedAlerts; omitted > 0 {
+		summary["omittedAlerts"] = omitted
+	}
+	payload, _ := json.Marshal(summary)
+	tc.Payload = security.SanitizeActionResult(string(payload), maxTriggerPayloadBytes)
+	return tc
+}
+
+// sanitizeAlertMap redacts secrets in label or annotation values and caps
+// their length.
+func sanitizeAlertMap(in map[string]string) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = security.SanitizeActionResult(v, maxAlertValueBytes)
+	}
+	return out
+}
+
+// alertSeverity maps an alert's severity label to an AgentEvent severity.
+func alertSeverity(labels map[string]string) corev1alpha1.AgentEventSeverity {
+	switch strings.ToLower(labels["severity"]) {
+	case "critical", "page":
+		return corev1alpha1.EventSeverityCritical
+	case "warning", "warn":
+		return corev1alpha1.EventSeverityWarning
+	default:
+		return corev1alpha1.EventSeverityInfo
+	}
+}
+
+// alertGroups dedupes Alertmanager notifications per agent and groupKey.
+// Alertmanager re-sends a group on every group_interval and repeat_interval;
+// only a notification carrying a firing alert not yet seen for the group
+// starts a new run. A resolved notification forgets the group.
+type alertGroups struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	groups map[string]*alertGroup
+}
+
+type alertGroup struct {
+	fingerprints map[string]bool
+	lastFired    time.Time
+}
+
+func newAlertGroups(ttl time.Duration) *alertGroups {
+	if ttl <= 0 {
+		ttl = defaultAlertGroupTTL
+	}
+	return &alertGroups{
+		ttl:    ttl,
+		groups: make(map[string]*alertGroup),
+	}
+}
+
+// alertGroupKey scopes a groupKey to an agent.
+func alertGroupKey(agentKey types.NamespacedName, groupKey string) string {
+	return agentKey.String() + "|" + groupKey
+}
+
+// observeFiring records a firing notification and reports whether it
+// should start a run.
+func (g *alertGroups) observeFiring(key string, alerts []AlertmanagerAlert) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	group, ok := g.groups[key]
+	if !ok || now.Sub(group.lastFired) > g.ttl {
+		group = &alertGroup{fingerprints: make(map[string]bool)}
+		g.groups[key] = group
+	}
+
+	fire := false
+	for _, a := range alerts {
+		fp := alertFingerprint(a)
+		if !group.fingerprints[fp] {
+			group.fingerprints[fp] = true
+			fire = true
+		}
+	}
+	if fire {
+		group.lastFired = now
+	}
+	return fire
+}
+
+// resolve forgets a group.
+func (g *alertGroups) resolve(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.groups, key)
+}
+
+// Clean removes groups not fired within the TTL.
+func (g *alertGroups) Clean() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	threshold := time.Now().Add(-g.ttl)
+	for key, group := range g.groups {
+		if group.lastFired.Before(threshold) {
+			delete(g.groups, key)
+		}
+	}
+}
+
+// alertFingerprint returns the alert's fingerprint, falling back to its
+// sorted label set for senders that don't populate one.
+func alertFingerprint(a AlertmanagerAlert) string {
+	if a.Fingerprint != "" {
+		return a.Fingerprint
+	}
+	keys := make([]string, 0, len(a.Labels))
+	for k := range a.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%q,", k, a.Labels[k])
+	}
+	return b.String()
+}
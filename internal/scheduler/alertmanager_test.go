@@ -0,0 +1,189 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/events"
+)
+
+func alertmanagerBody(t *testing.T, status string, fingerprints ...string) string {
+	t.Helper()
+	p := AlertmanagerPayload{
+		Version:           "4",
+		GroupKey:          `{}:{alertname="KubePodCrashLooping"}`,
+		Status:            status,
+		Receiver:          "legator",
+		GroupLabels:       map[string]string{"alertname": "KubePodCrashLooping"},
+		CommonLabels:      map[string]string{"alertname": "KubePodCrashLooping", "severity": "critical"},
+		CommonAnnotations: map[string]string{"summary": "Pod is crash looping"},
+	}
+	for _, fp := range fingerprints {
+		p.Alerts = append(p.Alerts, AlertmanagerAlert{
+			Status:      status,
+			Fingerprint: fp,
+			Labels:      map[string]string{"alertname": "KubePodCrashLooping", "pod": "backstage-" + fp},
+			Annotations: map[string]string{"description": "Back-off restarting failed container"},
+		})
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func expectAlertTrigger(t *testing.T, h *WebhookHandler) AlertTrigger {
+	t.Helper()
+	select {
+	case trigger := <-h.Alerts():
+		return trigger
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for alert trigger")
+		return AlertTrigger{}
+	}
+}
+
+func expectNoAlertTrigger(t *testing.T, h *WebhookHandler) {
+	t.Helper()
+	select {
+	case trigger := <-h.Alerts():
+		t.Fatalf("unexpected alert trigger for %v (%s)", trigger.AgentKey, trigger.Alert.Status)
+	default:
+	}
+}
+
+func TestParseAlertmanagerPayload(t *testing.T) {
+	if _, err := ParseAlertmanagerPayload([]byte(alertmanagerBody(t, AlertStatusFiring, "a1"))); err != nil {
+		t.Errorf("valid payload: %v", err)
+	}
+
+	for name, body := range map[string]string{
+		"not json":      `firing`,
+		"wrong version": `{"version":"3","groupKey":"g","status":"firing"}`,
+		"no groupKey":   `{"version":"4","status":"firing"}`,
+		"bad status":    `{"version":"4","groupKey":"g","status":"pending"}`,
+	} {
+		if _, err := ParseAlertmanagerPayload([]byte(body)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestWebhookHandler_AlertmanagerDedupesByGroupKey(t *testing.T) {
+	h := NewWebhookHandler(logf.Log.WithName("test"), time.Millisecond)
+	agentKey := types.NamespacedName{Namespace: "agents", Name: "watchman-deep"}
+	h.RegisterAlertmanagerAgent("alertmanager", agentKey, nil, nil)
+
+	if code := postWebhook(h, "alertmanager", alertmanagerBody(t, AlertStatusFiring, "a1"), nil); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	trigger := expectAlertTrigger(t, h)
+	if trigger.AgentKey != agentKey || trigger.Resolved() {
+		t.Errorf("expected firing trigger for %v, got %+v", agentKey, trigger)
+	}
+
+	// Repeat notification for the same group and alerts — deduped
+	postWebhook(h, "alertmanager", alertmanagerBody(t, AlertStatusFiring, "a1"), nil)
+	expectNoAlertTrigger(t, h)
+
+	// A new alert joins the group — fires again
+	postWebhook(h, "alertmanager", alertmanagerBody(t, AlertStatusFiring, "a1", "a2"), nil)
+	expectAlertTrigger(t, h)
+
+	// Resolved — no run, and the group is forgotten
+	postWebhook(h, "alertmanager", alertmanagerBody(t, AlertStatusResolved, "a1", "a2"), nil)
+	expectNoAlertTrigger(t, h)
+	postWebhook(h, "alertmanager", alertmanagerBody(t, AlertStatusFiring, "a1"), nil)
+	expectAlertTrigger(t, h)
+}
+
+func TestWebhookHandler_AlertmanagerRejectsInvalidPayload(t *testing.T) {
+	h := NewWebhookHandler(logf.Log.WithName("test"), time.Millisecond)
+	h.RegisterAlertmanagerAgent("alertmanager", types.NamespacedName{Namespace: "agents", Name: "watchman-deep"}, nil, nil)
+
+	if code := postWebhook(h, "alertmanager", `{"version":"3"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", code)
+	}
+	expectNoAlertTrigger(t, h)
+}
+
+func TestAlertTrigger_TriggerContext(t *testing.T) {
+	p, err := ParseAlertmanagerPayload([]byte(alertmanagerBody(t, AlertStatusFiring, "a1", "a2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := AlertTrigger{Source: "alertmanager", Alert: p}.TriggerContext()
+
+	if tc.GroupKey != p.GroupKey {
+		t.Errorf("expected groupKey %q, got %q", p.GroupKey, tc.GroupKey)
+	}
+	if tc.Labels["severity"] != "critical" {
+		t.Errorf("expected common label severity=critical, got %v", tc.Labels)
+	}
+	if tc.Annotations["summary"] != "Pod is crash looping" {
+		t.Errorf("expected common annotation summary, got %v", tc.Annotations)
+	}
+	if len(tc.Alerts) != 2 || tc.Alerts[1].Labels["pod"] != "backstage-a2" {
+		t.Errorf("expected 2 alerts with pod labels, got %+v", tc.Alerts)
+	}
+	if !strings.Contains(tc.Payload, `"pod":"backstage-a1"`) {
+		t.Errorf("payload missing alert labels: %s", tc.Payload)
+	}
+}
+
+func TestWebhookHandler_ResolvedAlertPublishesAgentEvent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&corev1alpha1.AgentEvent{}).Build()
+
+	h := NewWebhookHandler(logf.Log.WithName("test"), time.Millisecond).
+		WithEventBus(events.NewBus(c, logf.Log.WithName("events")))
+	h.RegisterAlertmanagerAgent("alertmanager", types.NamespacedName{Namespace: "agents", Name: "watchman-deep"}, nil, nil)
+
+	// No scheduler is draining the alert channel, and it is full
+	for len(h.alerts) < cap(h.alerts) {
+		h.alerts <- AlertTrigger{}
+	}
+
+	if code := postWebhook(h, "alertmanager", alertmanagerBody(t, AlertStatusResolved, "a1"), nil); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+
+	list := &corev1alpha1.AgentEventList{}
+	if err := c.List(context.Background(), list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("expected 1 AgentEvent, got %d", len(list.Items))
+	}
+	ev := list.Items[0].Spec
+	if ev.EventType != "alert.resolved" || ev.Severity != corev1alpha1.EventSeverityCritical || ev.SourceAgent != "watchman-deep" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if !strings.Contains(ev.Summary, "KubePodCrashLooping") {
+		t.Errorf("expected summary to name the alert, got %q", ev.Summary)
+	}
+}
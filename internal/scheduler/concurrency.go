@@ -11,61 +11,112 @@ You may obtain a copy of the License at
 package scheduler
 
 import (
+	"context"
 	"sync"
 	"time"
-)
-
-// ConcurrencyPolicy defines what to do when a run is requested while another is in progress.
-type ConcurrencyPolicy string
-
-const (
-	// ConcurrencySkip skips the new run if one is already in progress.
-	ConcurrencySkip ConcurrencyPolicy = "Skip"
 
-	// ConcurrencyQueue queues the new run to start after the current one completes.
-	// Not yet implemented — defaults to Skip behaviour.
-	ConcurrencyQueue ConcurrencyPolicy = "Queue"
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
 )
 
-// RunTracker tracks in-flight agent runs to enforce one-at-a-time concurrency.
+// RunTracker tracks in-flight agent runs to enforce per-agent concurrency.
 // Thread-safe.
 type RunTracker struct {
 	mu       sync.RWMutex
-	inflight map[string]*RunInfo
+	nextID   uint64
+	inflight map[string][]*RunInfo
+	// slots holds the latest schedule slot a run was started for, per agent.
+	slots map[string]time.Time
 }
 
 // RunInfo records metadata about an in-flight run.
 type RunInfo struct {
+	ID        uint64
 	RunName   string
 	StartedAt time.Time
+
+	// cancel aborts the run, from the moment it is tracked.
+	cancel context.CancelCauseFunc
 }
 
 // NewRunTracker creates a new tracker.
 func NewRunTracker() *RunTracker {
 	return &RunTracker{
-		inflight: make(map[string]*RunInfo),
+		inflight: make(map[string][]*RunInfo),
+		slots:    make(map[string]time.Time),
+	}
+}
+
+// Start attempts to record a new run for an agent. limit is the maximum
+// number of simultaneous runs for the agent; zero or less means unlimited.
+// Returns the run's ID (for Finish) and whether the run may proceed.
+func (t *RunTracker) Start(agentKey string, runName string, limit int) (uint64, bool) {
+	return t.StartWithCancel(agentKey, runName, limit, nil)
+}
+
+// StartWithCancel is Start for a run that Abort can cancel with cancel.
+func (t *RunTracker) StartWithCancel(agentKey string, runName string, limit int, cancel context.CancelCauseFunc) (uint64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if limit > 0 && len(t.inflight[agentKey]) >= limit {
+		return 0, false
 	}
+
+	t.nextID++
+	t.inflight[agentKey] = append(t.inflight[agentKey], &RunInfo{
+		ID:        t.nextID,
+		RunName:   runName,
+		StartedAt: time.Now(),
+		cancel:    cancel,
+	})
+	return t.nextID, true
 }
 
 // TryStart attempts to mark an agent as running.
 // Returns true if the agent was not already running (run may proceed).
 // Returns false if the agent already has an in-flight run (skip this one).
 func (t *RunTracker) TryStart(agentKey string, runName string) bool {
+	_, ok := t.Start(agentKey, runName, 1)
+	return ok
+}
+
+// Finish marks a single run, as returned by Start, as finished.
+func (t *RunTracker) Finish(agentKey string, id uint64) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if _, exists := t.inflight[agentKey]; exists {
-		return false
+	runs := t.inflight[agentKey]
+	for i, info := range runs {
+		if info.ID == id {
+			runs = append(runs[:i], runs[i+1:]...)
+			break
+		}
 	}
+	if len(runs) == 0 {
+		delete(t.inflight, agentKey)
+	} else {
+		t.inflight[agentKey] = runs
+	}
+}
 
-	t.inflight[agentKey] = &RunInfo{
-		RunName:   runName,
-		StartedAt: time.Now(),
+// Abort cancels the agent's tracked runs other than keep with cause, and
+// returns how many it cancelled. Runs are cancelled whether or not they have
+// reached the runner yet; each stays tracked until it finishes.
+func (t *RunTracker) Abort(agentKey string, keep uint64, cause error) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	aborted := 0
+	for _, info := range t.inflight[agentKey] {
+		if info.ID != keep && info.cancel != nil {
+			info.cancel(cause)
+			aborted++
+		}
 	}
-	return true
+	return aborted
 }
 
-// Complete marks an agent run as finished.
+// Complete marks all of an agent's runs as finished.
 func (t *RunTracker) Complete(agentKey string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -74,45 +125,104 @@ func (t *RunTracker) Complete(agentKey string) {
 
 // IsRunning returns true if the agent has an in-flight run.
 func (t *RunTracker) IsRunning(agentKey string) bool {
+	return t.RunCount(agentKey) > 0
+}
+
+// RunCount returns how many runs the agent has in flight.
+func (t *RunTracker) RunCount(agentKey string) int {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	_, exists := t.inflight[agentKey]
-	return exists
+	return len(t.inflight[agentKey])
 }
 
-// GetRunInfo returns info about an in-flight run, or nil if not running.
+// GetRunInfo returns info about the agent's oldest in-flight run, or nil if
+// not running.
 func (t *RunTracker) GetRunInfo(agentKey string) *RunInfo {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	info, exists := t.inflight[agentKey]
-	if !exists {
+	runs := t.inflight[agentKey]
+	if len(runs) == 0 {
 		return nil
 	}
 	// Return a copy
-	cp := *info
+	cp := *runs[0]
+	cp.cancel = nil
 	return &cp
 }
 
-// InFlightCount returns how many agents are currently running.
+// RecordSlot records that the agent's schedule slot was started or skipped.
+func (t *RunTracker) RecordSlot(agentKey string, slot time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if slot.After(t.slots[agentKey]) {
+		t.slots[agentKey] = slot
+	}
+}
+
+// LastSlot returns the latest schedule slot started or skipped, if any.
+func (t *RunTracker) LastSlot(agentKey string) (time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	slot, ok := t.slots[agentKey]
+	return slot, ok
+}
+
+// InFlightCount returns how many runs are currently in flight across all agents.
 func (t *RunTracker) InFlightCount() int {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	return len(t.inflight)
+	n := 0
+	for _, runs := range t.inflight {
+		n += len(runs)
+	}
+	return n
 }
 
 // CleanStale removes runs that have been in-flight longer than the given duration.
-// This handles the case where a run crashes without calling Complete().
+// This handles the case where a run crashes without calling Finish().
 func (t *RunTracker) CleanStale(maxAge time.Duration) int {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	cleaned := 0
 	now := time.Now()
-	for key, info := range t.inflight {
-		if now.Sub(info.StartedAt) > maxAge {
+	for key, runs := range t.inflight {
+		kept := runs[:0]
+		for _, info := range runs {
+			if now.Sub(info.StartedAt) > maxAge {
+				cleaned++
+				continue
+			}
+			kept = append(kept, info)
+		}
+		if len(kept) == 0 {
 			delete(t.inflight, key)
-			cleaned++
+		} else {
+			t.inflight[key] = kept
 		}
 	}
 	return cleaned
 }
+
+// concurrencyPolicy returns the agent's concurrency policy (default Forbid).
+func concurrencyPolicy(agent *corev1alpha1.LegatorAgent) corev1alpha1.ConcurrencyPolicy {
+	if p := agent.Spec.Schedule.ConcurrencyPolicy; p != "" {
+		return p
+	}
+	return corev1alpha1.ConcurrencyForbid
+}
+
+// concurrencyLimit returns how many runs the agent may have in flight:
+// maxConcurrentRuns (or defaultAllow) under Allow, otherwise one.
+func concurrencyLimit(agent *corev1alpha1.LegatorAgent, defaultAllow int) int {
+	if concurrencyPolicy(agent) != corev1alpha1.ConcurrencyAllow {
+		return 1
+	}
+	if n := agent.Spec.Schedule.MaxConcurrentRuns; n != nil && *n > 0 {
+		return int(*n)
+	}
+	if defaultAllow > 0 {
+		return defaultAllow
+	}
+	return 1
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/security"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+
+// EventTrigger is emitted when a Kubernetes Event matches an agent's
+// kubernetes-event trigger.
+type EventTrigger struct {
+	AgentKey types.NamespacedName
+	Event    *corev1.Event
+	Time     time.Time
+}
+
+// TriggerContext returns the run's trigger context: a summary of the Event,
+// sanitized and truncated to maxTriggerPayloadBytes.
+func (t EventTrigger) TriggerContext() *corev1alpha1.TriggerContext {
+	ev := t.Event
+	summary := map[string]interface{}{
+		"type":    ev.Type,
+		"reason":  ev.Reason,
+		"message": ev.Message,
+		"involvedObject": map[string]string{
+			"kind":      ev.InvolvedObject.Kind,
+			"namespace": ev.InvolvedObject.Namespace,
+			"name":      ev.InvolvedObject.Name,
+		},
+		"count": ev.Count,
+	}
+	if !ev.FirstTimestamp.IsZero() {
+		summary["firstTimestamp"] = ev.FirstTimestamp.UTC().Format(time.RFC3339)
+	}
+	if ts := eventTime(ev); !ts.IsZero() {
+		summary["lastTimestamp"] = ts.UTC().Format(time.RFC3339)
+	}
+	if ev.Source.Component != "" {
+		summary["source"] = ev.Source.Component
+	}
+
+	payload, _ := json.Marshal(summary)
+	return &corev1alpha1.TriggerContext{
+		Source:  string(corev1alpha1.TriggerKubernetesEvent),
+		Payload: security.SanitizeActionResult(string(payload), maxTriggerPayloadBytes),
+	}
+}
+
+// eventMatcher is one kubernetes-event trigger. Empty sets match anything.
+type eventMatcher struct {
+	kinds      map[string]bool
+	reasons    map[string]bool
+	namespaces map[string]bool
+}
+
+func newEventMatcher(trigger corev1alpha1.TriggerSpec, namespaces []string) eventMatcher {
+	m := eventMatcher{
+		kinds:      make(map[string]bool),
+		reasons:    make(map[string]bool),
+		namespaces: make(map[string]bool),
+	}
+	for _, r := range trigger.Resources {
+		m.kinds[strings.ToLower(r)] = true
+	}
+	for _, r := range trigger.Reasons {
+		m.reasons[r] = true
+	}
+	for _, ns := range namespaces {
+		m.namespaces[ns] = true
+	}
+	return m
+}
+
+// matches reports whether the Event's involved-object kind, reason and
+// namespace satisfy this trigger. Kinds are compared with
+// involvedObject.kind case-insensitively ("Pod", "pod"), never pluralised.
+func (m eventMatcher) matches(ev *corev1.Event) bool {
+	if len(m.reasons) > 0 && !m.reasons[ev.Reason] {
+		return false
+	}
+	if len(m.namespaces) > 0 && !m.namespaces[ev.InvolvedObject.Namespace] {
+		return false
+	}
+	if len(m.kinds) > 0 && !m.kinds[strings.ToLower(ev.InvolvedObject.Kind)] {
+		return false
+	}
+	return true
+}
+
+// EventSource matches core/v1 Events against registered kubernetes-event
+// triggers and emits EventTriggers. Events are fed in by an informer
+// (see Scheduler.ensureEventInformer); matching is debounced per agent,
+// involved object and reason.
+type EventSource struct {
+	mu        sync.RWMutex
+	log       logr.Logger
+	debouncer *Debouncer
+	triggers  chan EventTrigger
+
+	// matchers maps agents to their kubernetes-event triggers.
+	matchers map[types.NamespacedName][]eventMatcher
+
+	// since is when the source was created. Older events (replayed by the
+	// informer's initial list) are ignored.
+	since time.Time
+
+	// queue persists run triggers. Optional — if nil, triggers are handed
+	// to the scheduler over the in-memory channel.
+	queue *TriggerQueue
+
+	// pending holds matched triggers for Run to write to the queue, so the
+	// informer's handler never waits on the API server.
+	pending chan pendingEvent
+}
+
+// pendingEvent is a matched trigger waiting to be queued.
+type pendingEvent struct {
+	trigger     EventTrigger
+	debounceKey string
+}
+
+// NewEventSource creates a Kubernetes Event trigger source.
+func NewEventSource(log logr.Logger, debounceWindow time.Duration) *EventSource {
+	return &EventSource{
+		log:       log,
+		debouncer: NewDebouncer(debounceWindow),
+		triggers:  make(chan EventTrigger, 100),
+		pending:   make(chan pendingEvent, 100),
+		matchers:  make(map[types.NamespacedName][]eventMatcher),
+		since:     time.Now(),
+	}
+}
+
+// WithQueue sets the durable queue that run triggers are written to.
+func (s *EventSource) WithQueue(q *TriggerQueue) *EventSource {
+	s.queue = q
+	return s
+}
+
+// Triggers returns the channel of event trigger events.
+func (s *EventSource) Triggers() <-chan EventTrigger {
+	return s.triggers
+}
+
+// RegisterAgent adds a kubernetes-event trigger for an agent. If namespaces
+// is non-empty, only Events for objects in those namespaces match.
+func (s *EventSource) RegisterAgent(agentKey types.NamespacedName, trigger corev1alpha1.TriggerSpec, namespaces []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matchers[agentKey] = append(s.matchers[agentKey], newEventMatcher(trigger, namespaces))
+}
+
+// UnregisterAgent removes all kubernetes-event triggers for an agent.
+func (s *EventSource) UnregisterAgent(agentKey types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.matchers, agentKey)
+}
+
+// HasRegistrations reports whether any agent has a kubernetes-event trigger.
+func (s *EventSource) HasRegistrations() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.matchers) > 0
+}
+
+// Handle matches a single Event against all registered triggers.
+// Called by the informer on add and update (an update bumps the count of a
+// recurring Event, which is a fresh occurrence).
+func (s *EventSource) Handle(ev *corev1.Event) {
+	if ev == nil || eventTime(ev).Before(s.since) {
+		return
+	}
+
+	s.mu.RLock()
+	var matched []types.NamespacedName
+	for agentKey, matchers := range s.matchers {
+		for _, m := range matchers {
+			if m.matches(ev) {
+				matched = append(matched, agentKey)
+				break
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, agentKey := range matched {
+		debounceKey := fmt.Sprintf("event/%s/%s/%s/%s/%s/%s",
+			agentKey.Namespace, agentKey.Name,
+			ev.InvolvedObject.Kind, ev.InvolvedObject.Namespace, ev.InvolvedObject.Name, ev.Reason)
+		if !s.debouncer.ShouldFire(debounceKey) {
+			s.log.V(1).Info("Kubernetes event debounced",
+				"agent", agentKey.String(),
+				"reason", ev.Reason,
+				"object", ev.InvolvedObject.Kind+"/"+ev.InvolvedObject.Name,
+			)
+			continue
+		}
+
+		if err := s.emit(EventTrigger{AgentKey: agentKey, Event: ev.DeepCopy(), Time: time.Now()}, debounceKey); err != nil {
+			// Let the next update of this Event retry
+			s.debouncer.Forget(debounceKey)
+			s.log.Error(err, "Failed to queue event trigger",
+				"agent", agentKey.String())
+			continue
+		}
+		s.log.Info("Kubernetes event matched trigger",
+			"agent", agentKey.String(),
+			"reason", ev.Reason,
+			"object", ev.InvolvedObject.Kind+"/"+ev.InvolvedObject.Namespace+"/"+ev.InvolvedObject.Name,
+		)
+	}
+}
+
+// emit hands an event trigger to Run to be queued, or to the scheduler over
+// the in-memory channel when no queue is configured. It never blocks.
+func (s *EventSource) emit(trigger EventTrigger, debounceKey string) error {
+	if s.queue != nil {
+		select {
+		case s.pending <- pendingEvent{trigger: trigger, debounceKey: debounceKey}:
+			return nil
+		default:
+			return errTriggerChannelFull
+		}
+	}
+	select {
+	case s.triggers <- trigger:
+		return nil
+	default:
+		return errTriggerChannelFull
+	}
+}
+
+// Run writes matched triggers to the queue until ctx is done. A trigger
+// that can't be queued is dropped and its debounce forgotten, so the next
+// update of its Event retries.
+func (s *EventSource) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case p := <-s.pending:
+			enqueueCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			err := s.queue.Enqueue(enqueueCtx, p.trigger.AgentKey, corev1alpha1.RunTriggerEvent, p.trigger.TriggerContext())
+			cancel()
+			if err != nil {
+				s.debouncer.Forget(p.debounceKey)
+				s.log.Error(err, "Failed to queue event trigger",
+					"agent", p.trigger.AgentKey.String())
+			}
+		}
+	}
+}
+
+// eventTime returns when the Event last occurred, falling back through the
+// fields populated by the events/v1 and core/v1 APIs.
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case ev.Series != nil && !ev.Series.LastObservedTime.IsZero():
+		return ev.Series.LastObservedTime.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// environmentNamespaces flattens a namespace map into a list.
+func environmentNamespaces(nm *corev1alpha1.NamespaceMap) []string {
+	if nm == nil {
+		return nil
+	}
+	var out []string
+	out = append(out, nm.Monitoring...)
+	out = append(out, nm.Apps...)
+	out = append(out, nm.System...)
+	for _, group := range nm.Additional {
+		out = append(out, group...)
+	}
+	return out
+}
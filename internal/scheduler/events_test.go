@@ -0,0 +1,231 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+func testEvent(kind, namespace, name, reason string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name + ".17a", Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+		},
+		Reason:        reason,
+		Message:       "Back-off restarting failed container",
+		Type:          corev1.EventTypeWarning,
+		Count:         3,
+		LastTimestamp: metav1.Now(),
+	}
+}
+
+func podBackOffTrigger() corev1alpha1.TriggerSpec {
+	return corev1alpha1.TriggerSpec{
+		Type:      corev1alpha1.TriggerKubernetesEvent,
+		Resources: []string{"Pod"},
+		Reasons:   []string{"BackOff", "OOMKilling"},
+	}
+}
+
+func expectEventTrigger(t *testing.T, s *EventSource) EventTrigger {
+	t.Helper()
+	select {
+	case trigger := <-s.Triggers():
+		return trigger
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event trigger")
+		return EventTrigger{}
+	}
+}
+
+func expectNoEventTrigger(t *testing.T, s *EventSource) {
+	t.Helper()
+	select {
+	case trigger := <-s.Triggers():
+		t.Fatalf("unexpected trigger for %v (%s)", trigger.AgentKey, trigger.Event.Reason)
+	default:
+	}
+}
+
+func TestEventSource_MatchesKindAndReason(t *testing.T) {
+	s := NewEventSource(logf.Log.WithName("test"), time.Millisecond)
+	agentKey := types.NamespacedName{Namespace: "agents", Name: "tribune"}
+	s.RegisterAgent(agentKey, podBackOffTrigger(), nil)
+
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "BackOff"))
+
+	trigger := expectEventTrigger(t, s)
+	if trigger.AgentKey != agentKey {
+		t.Errorf("expected agent key %v, got %v", agentKey, trigger.AgentKey)
+	}
+	if trigger.Event.InvolvedObject.Name != "backstage-abc" {
+		t.Errorf("expected involved object backstage-abc, got %q", trigger.Event.InvolvedObject.Name)
+	}
+}
+
+func TestEventSource_NoMatch(t *testing.T) {
+	s := NewEventSource(logf.Log.WithName("test"), time.Millisecond)
+	s.RegisterAgent(types.NamespacedName{Namespace: "agents", Name: "tribune"}, podBackOffTrigger(), nil)
+
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "Scheduled"))
+	s.Handle(testEvent("Node", "", "talos-wk-01", "BackOff"))
+
+	expectNoEventTrigger(t, s)
+}
+
+func TestEventSource_NamespaceScope(t *testing.T) {
+	s := NewEventSource(logf.Log.WithName("test"), time.Millisecond)
+	s.RegisterAgent(types.NamespacedName{Namespace: "agents", Name: "tribune"}, podBackOffTrigger(), []string{"backstage"})
+
+	s.Handle(testEvent("Pod", "kube-system", "coredns-xyz", "BackOff"))
+	expectNoEventTrigger(t, s)
+
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "BackOff"))
+	expectEventTrigger(t, s)
+}
+
+func TestEventSource_Debounce(t *testing.T) {
+	s := NewEventSource(logf.Log.WithName("test"), time.Hour)
+	s.RegisterAgent(types.NamespacedName{Namespace: "agents", Name: "tribune"}, podBackOffTrigger(), nil)
+
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "BackOff"))
+	expectEventTrigger(t, s)
+
+	// Same object and reason within the window — dropped
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "BackOff"))
+	expectNoEventTrigger(t, s)
+
+	// Different object — fires
+	s.Handle(testEvent("Pod", "backstage", "backstage-def", "BackOff"))
+	expectEventTrigger(t, s)
+}
+
+func TestEventSource_IgnoresOldEvents(t *testing.T) {
+	s := NewEventSource(logf.Log.WithName("test"), time.Millisecond)
+	s.RegisterAgent(types.NamespacedName{Namespace: "agents", Name: "tribune"}, podBackOffTrigger(), nil)
+
+	ev := testEvent("Pod", "backstage", "backstage-abc", "BackOff")
+	ev.LastTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	s.Handle(ev)
+
+	expectNoEventTrigger(t, s)
+}
+
+func TestEventSource_UnregisterAgent(t *testing.T) {
+	s := NewEventSource(logf.Log.WithName("test"), time.Millisecond)
+	agentKey := types.NamespacedName{Namespace: "agents", Name: "tribune"}
+	s.RegisterAgent(agentKey, podBackOffTrigger(), nil)
+	if !s.HasRegistrations() {
+		t.Fatal("expected registrations after RegisterAgent")
+	}
+
+	s.UnregisterAgent(agentKey)
+	if s.HasRegistrations() {
+		t.Error("expected no registrations after UnregisterAgent")
+	}
+
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "BackOff"))
+	expectNoEventTrigger(t, s)
+}
+
+func TestEventSource_QueuesOffTheInformerThread(t *testing.T) {
+	c := newQueueTestClient(t)
+	s := NewEventSource(logf.Log.WithName("test"), time.Millisecond).
+		WithQueue(NewTriggerQueue(c, logf.Log.WithName("test")))
+	s.RegisterAgent(types.NamespacedName{Namespace: "agents", Name: "tribune"}, podBackOffTrigger(), nil)
+
+	// The handler returns without writing to the API server
+	s.Handle(testEvent("Pod", "backstage", "backstage-abc", "BackOff"))
+	if items := listRunRequests(t, c); len(items) != 0 {
+		t.Fatalf("expected nothing queued before Run, got %d RunRequests", len(items))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for len(listRunRequests(t, c)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for Run to queue the trigger")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	items := listRunRequests(t, c)
+	if len(items) != 1 || items[0].Spec.AgentRef != "tribune" || items[0].Spec.Trigger != corev1alpha1.RunTriggerEvent {
+		t.Errorf("unexpected RunRequests %+v", items)
+	}
+}
+
+func TestEventMatcher_KindForms(t *testing.T) {
+	tests := []struct {
+		resource string
+		kind     string
+		want     bool
+	}{
+		{"Pod", "Pod", true},
+		{"pod", "Pod", true},
+		{"NetworkPolicy", "NetworkPolicy", true},
+		{"Ingress", "Ingress", true},
+		{"Ingress", "IngressClass", false},
+		{"pods", "Pod", false},
+		{"Deployment", "Pod", false},
+	}
+
+	for _, tt := range tests {
+		m := newEventMatcher(corev1alpha1.TriggerSpec{Resources: []string{tt.resource}}, nil)
+		ev := testEvent(tt.kind, "default", "x", "Any")
+		if got := m.matches(ev); got != tt.want {
+			t.Errorf("resource %q vs kind %q: got %v, want %v", tt.resource, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestEventTrigger_TriggerContext(t *testing.T) {
+	trigger := EventTrigger{Event: testEvent("Pod", "backstage", "backstage-abc", "BackOff")}
+
+	tc := trigger.TriggerContext()
+	if tc.Source != "kubernetes-event" {
+		t.Errorf("expected source 'kubernetes-event', got %q", tc.Source)
+	}
+	for _, want := range []string{`"reason":"BackOff"`, `"name":"backstage-abc"`, `"kind":"Pod"`} {
+		if !strings.Contains(tc.Payload, want) {
+			t.Errorf("payload missing %s: %s", want, tc.Payload)
+		}
+	}
+}
+
+func TestEnvironmentNamespaces(t *testing.T) {
+	got := environmentNamespaces(&corev1alpha1.NamespaceMap{
+		Monitoring: []string{"monitoring"},
+		Apps:       []string{"backstage"},
+		Additional: map[string][]string{"data": {"postgres"}},
+	})
+	if len(got) != 3 {
+		t.Errorf("expected 3 namespaces, got %v", got)
+	}
+	if environmentNamespaces(nil) != nil {
+		t.Error("nil namespace map should mean all namespaces")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+// filterEnv is the CEL environment for trigger filters. The decoded JSON
+// payload is bound to `payload`.
+var filterEnv, filterEnvErr = cel.NewEnv(
+	cel.Variable("payload", cel.DynType),
+)
+
+const (
+	// filterCostLimit bounds the CEL runtime cost of one filter evaluation,
+	// so an expensive expression can't stall the webhook handler.
+	filterCostLimit = 100000
+
+	// filterTimeout bounds the wall-clock time of one filter evaluation.
+	filterTimeout = 100 * time.Millisecond
+)
+
+// PayloadFilter is a compiled TriggerSpec.Filter expression, e.g.
+//
+//	payload.status == "firing" && payload.commonLabels.severity == "critical"
+type PayloadFilter struct {
+	expr    string
+	program cel.Program
+}
+
+// CompileFilter parses and type-checks a filter expression. The expression
+// must evaluate to a bool.
+func CompileFilter(expr string) (*PayloadFilter, error) {
+	if filterEnvErr != nil {
+		return nil, fmt.Errorf("CEL environment: %w", filterEnvErr)
+	}
+
+	ast, iss := filterEnv.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("invalid filter %q: must evaluate to bool, got %s", expr, ast.OutputType())
+	}
+
+	program, err := filterEnv.Program(ast,
+		cel.CostLimit(filterCostLimit),
+		cel.InterruptCheckFrequency(100),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	return &PayloadFilter{expr: expr, program: program}, nil
+}
+
+// Match evaluates the filter against a JSON payload. A payload that isn't
+// JSON, a missing field, a non-bool result, or an evaluation that exceeds
+// the cost limit or timeout is an error (and no match).
+func (f *PayloadFilter) Match(ctx context.Context, body []byte) (bool, error) {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false, fmt.Errorf("payload is not JSON: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, filterTimeout)
+	defer cancel()
+
+	out, _, err := f.program.ContextEval(ctx, map[string]interface{}{"payload": payload})
+	if err != nil {
+		return false, fmt.Errorf("evaluating filter %q: %w", f.expr, err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("filter %q returned %T, want bool", f.expr, out.Value())
+	}
+	return matched, nil
+}
+
+// ValidateTriggerFilters compiles every trigger filter, returning the first
+// error. Called when a LegatorAgent is reconciled.
+func ValidateTriggerFilters(triggers []corev1alpha1.TriggerSpec) error {
+	for i, t := range triggers {
+		if t.Filter == "" {
+			continue
+		}
+		if _, err := CompileFilter(t.Filter); err != nil {
+			return fmt.Errorf("triggers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+const criticalFiringFilter = `payload.status == "firing" && payload.commonLabels.severity == "critical"`
+
+func TestCompileFilter_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		`payload.status ==`,
+		`payload.status + 1`,
+		`"firing"`,
+		`unknown.status == "firing"`,
+	} {
+		if _, err := CompileFilter(expr); err == nil {
+			t.Errorf("expected compile error for %q", expr)
+		}
+	}
+}
+
+func TestPayloadFilter_Match(t *testing.T) {
+	f, err := CompileFilter(criticalFiringFilter)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		want    bool
+		wantErr bool
+	}{
+		{"critical firing", `{"status":"firing","commonLabels":{"severity":"critical"}}`, true, false},
+		{"warning firing", `{"status":"firing","commonLabels":{"severity":"warning"}}`, false, false},
+		{"resolved", `{"status":"resolved","commonLabels":{"severity":"critical"}}`, false, false},
+		{"missing field", `{"status":"firing"}`, false, true},
+		{"not json", `status=firing`, false, true},
+	}
+	for _, tt := range tests {
+		got, err := f.Match(context.Background(), []byte(tt.body))
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPayloadFilter_CostLimit(t *testing.T) {
+	// Nested comprehensions over the payload cost far more than the limit
+	f, err := CompileFilter(`payload.items.all(a, payload.items.all(b, payload.items.all(c, a + b + c >= 0)))`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	items := make([]string, 200)
+	for i := range items {
+		items[i] = "1"
+	}
+	body := `{"items":[` + strings.Join(items, ",") + `]}`
+
+	matched, err := f.Match(context.Background(), []byte(body))
+	if err == nil || matched {
+		t.Errorf("expected the cost limit to stop evaluation, got matched=%v err=%v", matched, err)
+	}
+}
+
+func TestPayloadFilter_Cancelled(t *testing.T) {
+	// Cheap enough for the cost limit, long enough to be interrupted
+	f, err := CompileFilter(`payload.items.all(a, a >= 0)`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	items := make([]string, 1000)
+	for i := range items {
+		items[i] = "1"
+	}
+	body := `{"items":[` + strings.Join(items, ",") + `]}`
+	if matched, err := f.Match(context.Background(), []byte(body)); err != nil || !matched {
+		t.Fatalf("expected a match, got matched=%v err=%v", matched, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Match(ctx, []byte(body)); err == nil {
+		t.Error("expected a cancelled evaluation to fail")
+	}
+}
+
+func TestValidateTriggerFilters(t *testing.T) {
+	triggers := []corev1alpha1.TriggerSpec{
+		{Type: corev1alpha1.TriggerWebhook, Source: "alertmanager", Filter: criticalFiringFilter},
+		{Type: corev1alpha1.TriggerWebhook, Source: "github"},
+	}
+	if err := ValidateTriggerFilters(triggers); err != nil {
+		t.Errorf("expected valid filters, got %v", err)
+	}
+
+	triggers = append(triggers, corev1alpha1.TriggerSpec{Type: corev1alpha1.TriggerWebhook, Source: "ci", Filter: `payload.build ==`})
+	if err := ValidateTriggerFilters(triggers); err == nil {
+		t.Error("expected error for invalid filter")
+	}
+}
+
+func TestWebhookHandler_FilterSkipsNonMatchingAgents(t *testing.T) {
+	h := NewWebhookHandler(logf.Log.WithName("test"), time.Millisecond)
+	f, err := CompileFilter(criticalFiringFilter)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	critical := types.NamespacedName{Namespace: "agents", Name: "watchman-deep"}
+	h.RegisterAgentWithAuth("alertmanager", critical, nil, f)
+
+	body := `{"status":"firing","commonLabels":{"severity":"warning"}}`
+	if code := postWebhook(h, "alertmanager", body, nil); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	select {
+	case trigger := <-h.Triggers():
+		t.Fatalf("filtered agent %v triggered on a warning", trigger.AgentKey)
+	default:
+	}
+
+	body = `{"status":"firing","commonLabels":{"severity":"critical"}}`
+	if code := postWebhook(h, "alertmanager", body, nil); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	select {
+	case trigger := <-h.Triggers():
+		if trigger.AgentKey != critical {
+			t.Errorf("expected %v, got %v", critical, trigger.AgentKey)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for trigger")
+	}
+}
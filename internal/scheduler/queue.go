@@ -0,0 +1,215 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/metrics"
+)
+
+// +kubebuilder:rbac:groups=legator.io,resources=runrequests,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=legator.io,resources=runrequests/status,verbs=get;update;patch
+
+const (
+	// maxQueueAttempts is how many dispatch attempts a RunRequest gets
+	// before it is marked Failed.
+	maxQueueAttempts = 20
+
+	// queueBaseBackoff and queueMaxBackoff bound the retry delay.
+	queueBaseBackoff = 5 * time.Second
+	queueMaxBackoff  = 5 * time.Minute
+
+	// failedRequestRetention is how long Failed RunRequests are kept for
+	// inspection before being deleted.
+	failedRequestRetention = 24 * time.Hour
+
+	// labelAgent labels RunRequests with the agent they target.
+	labelAgent = "legator.io/agent"
+)
+
+// TriggerQueue persists pending triggers as RunRequest CRs, so a burst of
+// triggers or a controller restart never loses one. Any replica may enqueue
+// (the webhook listener runs on all of them); the leader's scheduler drains
+// the queue in creation order, retrying with exponential backoff.
+type TriggerQueue struct {
+	client client.Client
+	log    logr.Logger
+
+	// wake is signalled on enqueue so the scheduler drains immediately
+	// rather than on its next tick.
+	wake chan struct{}
+}
+
+// NewTriggerQueue creates a RunRequest-backed trigger queue.
+func NewTriggerQueue(c client.Client, log logr.Logger) *TriggerQueue {
+	return &TriggerQueue{
+		client: c,
+		log:    log,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Wake returns a channel signalled when a trigger is enqueued.
+func (q *TriggerQueue) Wake() <-chan struct{} {
+	return q.wake
+}
+
+// Enqueue persists a trigger for an agent.
+func (q *TriggerQueue) Enqueue(
+	ctx context.Context,
+	agentKey types.NamespacedName,
+	trigger corev1alpha1.RunTrigger,
+	triggerCtx *corev1alpha1.TriggerContext,
+) error {
+	rr := &corev1alpha1.RunRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: agentKey.Name + "-",
+			Namespace:    agentKey.Namespace,
+			Labels: map[string]string{
+				labelAgent: agentKey.Name,
+			},
+		},
+		Spec: corev1alpha1.RunRequestSpec{
+			AgentRef:       agentKey.Name,
+			Trigger:        trigger,
+			TriggerContext: triggerCtx,
+		},
+	}
+	if err := q.client.Create(ctx, rr); err != nil {
+		return fmt.Errorf("create RunRequest: %w", err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Drain dispatches due RunRequests in creation order. dispatch returns nil
+// once a request is handled (its run started, or it can be dropped) and the
+// request is deleted; an error schedules a retry. After one request for an
+// agent is deferred, that agent's later requests wait too, preserving order.
+func (q *TriggerQueue) Drain(ctx context.Context, dispatch func(context.Context, *corev1alpha1.RunRequest) error) {
+	list := &corev1alpha1.RunRequestList{}
+	if err := q.client.List(ctx, list); err != nil {
+		q.log.Error(err, "Failed to list RunRequests")
+		return
+	}
+
+	items := list.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		ti, tj := items[i].CreationTimestamp, items[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	now := time.Now()
+	pending, failed := 0, 0
+	deferred := make(map[types.NamespacedName]bool)
+	for i := range items {
+		rr := &items[i]
+		agentKey := types.NamespacedName{Namespace: rr.Namespace, Name: rr.Spec.AgentRef}
+
+		if rr.Status.Phase == corev1alpha1.RunRequestPhaseFailed {
+			if now.Sub(rr.CreationTimestamp.Time) > failedRequestRetention {
+				q.delete(ctx, rr)
+				continue
+			}
+			failed++
+			continue
+		}
+
+		if deferred[agentKey] || (rr.Status.NextAttemptTime != nil && now.Before(rr.Status.NextAttemptTime.Time)) {
+			deferred[agentKey] = true
+			pending++
+			continue
+		}
+
+		if err := dispatch(ctx, rr); err != nil {
+			deferred[agentKey] = true
+			if q.retry(ctx, rr, err, now) {
+				pending++
+			} else {
+				failed++
+			}
+			continue
+		}
+		q.delete(ctx, rr)
+	}
+
+	metrics.RecordTriggerQueueDepth(pending, failed)
+}
+
+// retry records a failed attempt and schedules the next one. It returns
+// false once the request has exhausted its attempts and is marked Failed.
+func (q *TriggerQueue) retry(ctx context.Context, rr *corev1alpha1.RunRequest, cause error, now time.Time) bool {
+	rr.Status.Attempts++
+	rr.Status.LastError = cause.Error()
+
+	retrying := rr.Status.Attempts < maxQueueAttempts
+	if retrying {
+		rr.Status.Phase = corev1alpha1.RunRequestPhasePending
+		rr.Status.NextAttemptTime = &metav1.Time{Time: now.Add(queueBackoff(rr.Status.Attempts))}
+		q.log.V(1).Info("Queued trigger deferred",
+			"runRequest", rr.Namespace+"/"+rr.Name,
+			"agent", rr.Spec.AgentRef,
+			"attempts", rr.Status.Attempts,
+			"reason", rr.Status.LastError,
+		)
+	} else {
+		rr.Status.Phase = corev1alpha1.RunRequestPhaseFailed
+		rr.Status.NextAttemptTime = nil
+		q.log.Info("Queued trigger failed — retries exhausted",
+			"runRequest", rr.Namespace+"/"+rr.Name,
+			"agent", rr.Spec.AgentRef,
+			"attempts", rr.Status.Attempts,
+			"reason", rr.Status.LastError,
+		)
+	}
+
+	if err := q.client.Status().Update(ctx, rr); err != nil {
+		q.log.Error(err, "Failed to update RunRequest status", "runRequest", rr.Namespace+"/"+rr.Name)
+	}
+	return retrying
+}
+
+func (q *TriggerQueue) delete(ctx context.Context, rr *corev1alpha1.RunRequest) {
+	if err := q.client.Delete(ctx, rr); err != nil && !errors.IsNotFound(err) {
+		q.log.Error(err, "Failed to delete RunRequest", "runRequest", rr.Namespace+"/"+rr.Name)
+	}
+}
+
+// queueBackoff returns the delay before the given attempt: exponential from
+// queueBaseBackoff, capped at queueMaxBackoff.
+func queueBackoff(attempts int32) time.Duration {
+	d := queueBaseBackoff
+	for i := int32(1); i < attempts; i++ {
+		d *= 2
+		if d >= queueMaxBackoff {
+			return queueMaxBackoff
+		}
+	}
+	return d
+}
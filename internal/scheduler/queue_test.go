@@ -0,0 +1,210 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+func newQueueTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&corev1alpha1.RunRequest{}).
+		WithObjects(objs...).
+		Build()
+}
+
+func listRunRequests(t *testing.T, c client.Client) []corev1alpha1.RunRequest {
+	t.Helper()
+	list := &corev1alpha1.RunRequestList{}
+	if err := c.List(context.Background(), list); err != nil {
+		t.Fatal(err)
+	}
+	return list.Items
+}
+
+func TestTriggerQueue_EnqueueAndDrain(t *testing.T) {
+	c := newQueueTestClient(t)
+	q := NewTriggerQueue(c, logf.Log.WithName("test"))
+	agentKey := types.NamespacedName{Namespace: "agents", Name: "watchman-light"}
+
+	tc := &corev1alpha1.TriggerContext{Source: "alertmanager", Payload: `{"status":"firing"}`}
+	if err := q.Enqueue(context.Background(), agentKey, corev1alpha1.RunTriggerWebhook, tc); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	select {
+	case <-q.Wake():
+	default:
+		t.Error("expected wake signal after enqueue")
+	}
+
+	items := listRunRequests(t, c)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 RunRequest, got %d", len(items))
+	}
+	if items[0].Spec.AgentRef != "watchman-light" || items[0].Labels[labelAgent] != "watchman-light" {
+		t.Errorf("unexpected RunRequest: %+v", items[0])
+	}
+
+	var dispatched []*corev1alpha1.RunRequest
+	q.Drain(context.Background(), func(_ context.Context, rr *corev1alpha1.RunRequest) error {
+		dispatched = append(dispatched, rr)
+		return nil
+	})
+	if len(dispatched) != 1 || dispatched[0].Spec.TriggerContext.Payload != tc.Payload {
+		t.Fatalf("expected the queued trigger to be dispatched, got %v", dispatched)
+	}
+	if items := listRunRequests(t, c); len(items) != 0 {
+		t.Errorf("expected dispatched RunRequest to be deleted, %d remain", len(items))
+	}
+}
+
+func TestTriggerQueue_RetryDefersAgent(t *testing.T) {
+	c := newQueueTestClient(t)
+	q := NewTriggerQueue(c, logf.Log.WithName("test"))
+	agentKey := types.NamespacedName{Namespace: "agents", Name: "watchman-light"}
+	for range 2 {
+		if err := q.Enqueue(context.Background(), agentKey, corev1alpha1.RunTriggerWebhook, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := 0
+	q.Drain(context.Background(), func(context.Context, *corev1alpha1.RunRequest) error {
+		calls++
+		return errAgentRunning
+	})
+	if calls != 1 {
+		t.Errorf("expected the agent's second request to wait behind the first, got %d dispatches", calls)
+	}
+
+	items := listRunRequests(t, c)
+	if len(items) != 2 {
+		t.Fatalf("expected both RunRequests to remain queued, got %d", len(items))
+	}
+	retried := 0
+	for _, rr := range items {
+		if rr.Status.Attempts == 1 {
+			retried++
+			if rr.Status.NextAttemptTime == nil || rr.Status.LastError != errAgentRunning.Error() {
+				t.Errorf("expected backoff and lastError on retried request, got %+v", rr.Status)
+			}
+		}
+	}
+	if retried != 1 {
+		t.Errorf("expected exactly one request with an attempt recorded, got %d", retried)
+	}
+
+	// Not yet due — nothing dispatched
+	q.Drain(context.Background(), func(context.Context, *corev1alpha1.RunRequest) error {
+		t.Error("dispatched a request before its backoff elapsed")
+		return nil
+	})
+}
+
+func TestTriggerQueue_ExhaustedRetriesFail(t *testing.T) {
+	rr := &corev1alpha1.RunRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "forge-abc", Namespace: "agents"},
+		Spec:       corev1alpha1.RunRequestSpec{AgentRef: "forge", Trigger: corev1alpha1.RunTriggerWebhook},
+		Status:     corev1alpha1.RunRequestStatus{Attempts: maxQueueAttempts - 1},
+	}
+	c := newQueueTestClient(t, rr)
+	q := NewTriggerQueue(c, logf.Log.WithName("test"))
+
+	q.Drain(context.Background(), func(context.Context, *corev1alpha1.RunRequest) error {
+		return errors.New("rate limited")
+	})
+
+	items := listRunRequests(t, c)
+	if len(items) != 1 || items[0].Status.Phase != corev1alpha1.RunRequestPhaseFailed {
+		t.Fatalf("expected RunRequest marked Failed, got %+v", items)
+	}
+
+	q.Drain(context.Background(), func(context.Context, *corev1alpha1.RunRequest) error {
+		t.Error("dispatched a Failed request")
+		return nil
+	})
+}
+
+func TestQueueBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{10, queueMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := queueBackoff(tt.attempts); got != tt.want {
+			t.Errorf("queueBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookHandler_QueuesTriggers(t *testing.T) {
+	c := newQueueTestClient(t)
+	h := NewWebhookHandler(logf.Log.WithName("test"), time.Millisecond).
+		WithQueue(NewTriggerQueue(c, logf.Log.WithName("test")))
+
+	// More triggers than the old in-memory channel held
+	for i := range 150 {
+		h.RegisterAgent("alertmanager", types.NamespacedName{Namespace: "agents", Name: fmt.Sprintf("agent-%d", i)})
+	}
+	if code := postWebhook(h, "alertmanager", `{"status":"firing"}`, nil); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	if got := len(listRunRequests(t, c)); got != 150 {
+		t.Errorf("expected 150 queued RunRequests, got %d", got)
+	}
+}
+
+func TestScheduler_DispatchRunRequestDropsPausedAgent(t *testing.T) {
+	agent := &corev1alpha1.LegatorAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "forge", Namespace: "agents"},
+		Spec:       corev1alpha1.LegatorAgentSpec{Paused: true},
+	}
+	c := newQueueTestClient(t, agent)
+	s := New(c, nil, logf.Log.WithName("test"), DefaultConfig())
+
+	rr := &corev1alpha1.RunRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "forge-abc", Namespace: "agents"},
+		Spec:       corev1alpha1.RunRequestSpec{AgentRef: "forge", Trigger: corev1alpha1.RunTriggerWebhook},
+	}
+	if err := s.dispatchRunRequest(context.Background(), rr); err != nil {
+		t.Errorf("paused agent: expected trigger to be dropped, got %v", err)
+	}
+
+	rr.Spec.AgentRef = "deleted"
+	if err := s.dispatchRunRequest(context.Background(), rr); err != nil {
+		t.Errorf("missing agent: expected trigger to be dropped, got %v", err)
+	}
+}
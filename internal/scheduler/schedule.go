@@ -52,68 +52,270 @@ func NextRun(agent *corev1alpha1.LegatorAgent, now time.Time) (time.Time, error)
 }
 
 // IsDue returns true if the agent should run now.
-// An agent is due if its next scheduled run time has passed
-// and it hasn't already run since that time.
+// An agent is due if a scheduled slot has passed since it last ran and the
+// agent's catch-up policy and starting deadline allow that slot to start.
 func IsDue(agent *corev1alpha1.LegatorAgent, now time.Time) (bool, error) {
+	d, err := EvaluateSchedule(agent, time.Time{}, now)
+	return d.Due, err
+}
+
+const (
+	// noCatchUpGrace is how late an on-time slot may start under catchUp
+	// "none" when no startingDeadline is set: a few scheduler ticks.
+	noCatchUpGrace = time.Minute
+
+	// maxSlotCount caps how many cron slots are walked to count missed
+	// slots. It only bounds the Missed and Remaining counts; which slot
+	// runs is found without walking.
+	maxSlotCount = 1000
+)
+
+// ScheduleDecision is the outcome of evaluating an agent's schedule slots
+// since it last ran.
+type ScheduleDecision struct {
+	// Due is true if a run should start now.
+	Due bool
+
+	// Slot is the scheduled time the run covers. If not due but Missed > 0,
+	// it is the latest skipped slot.
+	Slot time.Time
+
+	// Missed is how many slots were skipped by this decision.
+	Missed int
+
+	// Remaining is how many further missed slots will be run after this one
+	// (catchUp "all").
+	Remaining int
+}
+
+// EvaluateSchedule finds the schedule slots that have passed since the
+// agent last ran and applies the catch-up policy and starting deadline.
+// lastSlot is the latest slot the scheduler has already started or skipped
+// (zero if none); cron slots are counted from it so catch-up runs don't
+// hide the slots still owed.
+func EvaluateSchedule(agent *corev1alpha1.LegatorAgent, lastSlot, now time.Time) (ScheduleDecision, error) {
 	if agent.Spec.Paused {
-		return false, nil
+		return ScheduleDecision{}, nil
 	}
 
 	// Must have a cron or interval schedule
-	if agent.Spec.Schedule.Cron == "" && agent.Spec.Schedule.Interval == "" {
-		return false, nil
+	spec := agent.Spec.Schedule
+	if spec.Cron == "" && spec.Interval == "" {
+		return ScheduleDecision{}, nil
 	}
 
+	ref := scheduleReference(agent, lastSlot)
+
 	// If never run, it's due
-	if agent.Status.LastRunTime == nil {
-		return true, nil
+	if ref.IsZero() {
+		return ScheduleDecision{Due: true, Slot: now}, nil
 	}
 
-	lastRun := agent.Status.LastRunTime.Time
+	deadline, err := startingDeadline(agent)
+	if err != nil {
+		return ScheduleDecision{}, err
+	}
 
-	// Compute what the next run after the last run would be
-	nextAfterLast, err := nextRunAfter(agent, lastRun)
+	slots, err := newSlotClock(agent, ref)
 	if err != nil {
-		return false, err
+		return ScheduleDecision{}, err
+	}
+	latest := slots.prev(now)
+	if latest.IsZero() {
+		return ScheduleDecision{}, nil
+	}
+
+	inDeadline := func(slot time.Time) bool {
+		return deadline <= 0 || now.Sub(slot) <= deadline
+	}
+
+	switch catchUpPolicy(agent) {
+	case corev1alpha1.CatchUpAll:
+		first := slots.next(ref)
+		if !inDeadline(first) {
+			// Oldest slot still within the deadline
+			first = slots.next(now.Add(-deadline - time.Nanosecond))
+		}
+		if first.Before(now) {
+			return ScheduleDecision{
+				Due:       true,
+				Slot:      first,
+				Missed:    slots.count(ref, first),
+				Remaining: slots.count(first, now),
+			}, nil
+		}
+	case corev1alpha1.CatchUpNone:
+		if deadline <= 0 {
+			deadline = noCatchUpGrace
+		}
+		fallthrough
+	default:
+		if inDeadline(latest) {
+			return ScheduleDecision{Due: true, Slot: latest, Missed: slots.count(ref, latest)}, nil
+		}
 	}
 
-	return !nextAfterLast.IsZero() && now.After(nextAfterLast), nil
+	// Every slot is past its starting deadline
+	return ScheduleDecision{Slot: latest, Missed: slots.count(ref, now)}, nil
 }
 
-// --- Cron (Step 3.1) ---
+// scheduleReference returns the time missed slots are counted from. Cron
+// slots count from the last slot the scheduler handled, falling back to the
+// last run; interval slots count from whichever is later, keeping intervals
+// measured from the previous run.
+func scheduleReference(agent *corev1alpha1.LegatorAgent, lastSlot time.Time) time.Time {
+	var lastRun time.Time
+	if agent.Status.LastRunTime != nil {
+		lastRun = agent.Status.LastRunTime.Time
+	}
 
-// nextCronRun parses a cron expression and returns the next fire time after now.
-func nextCronRun(expr string, now time.Time) (time.Time, error) {
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	sched, err := parser.Parse(expr)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	if agent.Spec.Schedule.Cron != "" && !lastSlot.IsZero() {
+		return lastSlot
 	}
-	return sched.Next(now), nil
+	if lastSlot.After(lastRun) {
+		return lastSlot
+	}
+	return lastRun
 }
 
-// nextRunAfter computes the next run time after a given reference time.
-func nextRunAfter(agent *corev1alpha1.LegatorAgent, after time.Time) (time.Time, error) {
+// slotClock finds an agent's schedule slots after ref without walking every
+// slot in between: interval slots are ref plus a multiple of the interval,
+// and the latest cron slot is found by widening a window back from now.
+type slotClock struct {
+	ref      time.Time
+	cron     cron.Schedule
+	loc      *time.Location
+	interval time.Duration
+}
+
+func newSlotClock(agent *corev1alpha1.LegatorAgent, ref time.Time) (*slotClock, error) {
 	spec := agent.Spec.Schedule
+	c := &slotClock{ref: ref}
 
-	loc, err := loadTimezone(spec.Timezone)
+	if spec.Cron != "" {
+		loc, err := loadTimezone(spec.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", spec.Timezone, err)
+		}
+		sched, err := parseCron(spec.Cron)
+		if err != nil {
+			return nil, err
+		}
+		c.cron, c.loc = sched, loc
+		return c, nil
+	}
+
+	dur, err := time.ParseDuration(spec.Interval)
 	if err != nil {
-		return time.Time{}, err
+		return nil, fmt.Errorf("invalid interval %q: %w", spec.Interval, err)
 	}
+	if dur <= 0 {
+		return nil, fmt.Errorf("invalid interval %q: must be positive", spec.Interval)
+	}
+	c.interval = dur
+	return c, nil
+}
 
-	if spec.Cron != "" {
-		return nextCronRun(spec.Cron, after.In(loc))
+// next returns the first slot after t (and after ref).
+func (c *slotClock) next(t time.Time) time.Time {
+	if t.Before(c.ref) {
+		t = c.ref
 	}
+	if c.cron != nil {
+		return c.cron.Next(t.In(c.loc))
+	}
+	return c.ref.Add((t.Sub(c.ref)/c.interval + 1) * c.interval)
+}
 
-	if spec.Interval != "" {
-		dur, err := time.ParseDuration(spec.Interval)
-		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid interval %q: %w", spec.Interval, err)
+// prev returns the latest slot after ref and before t, or zero if none.
+func (c *slotClock) prev(t time.Time) time.Time {
+	if c.cron == nil {
+		elapsed := t.Sub(c.ref)
+		if elapsed <= c.interval {
+			return time.Time{}
 		}
-		return after.Add(dur), nil
+		return c.ref.Add((elapsed - 1) / c.interval * c.interval)
 	}
 
-	return time.Time{}, nil
+	for window := time.Minute; ; window *= 2 {
+		from := t.Add(-window)
+		if from.Before(c.ref) {
+			from = c.ref
+		}
+		slot := c.cron.Next(from.In(c.loc))
+		if !slot.IsZero() && slot.Before(t) {
+			for {
+				n := c.cron.Next(slot)
+				if n.IsZero() || !n.Before(t) {
+					return slot
+				}
+				slot = n
+			}
+		}
+		if from.Equal(c.ref) {
+			return time.Time{}
+		}
+	}
+}
+
+// count returns how many slots fall strictly between from and to, capped at
+// maxSlotCount for cron schedules.
+func (c *slotClock) count(from, to time.Time) int {
+	if c.cron == nil {
+		first := c.next(from)
+		if !first.Before(to) {
+			return 0
+		}
+		return int((to.Sub(first)-1)/c.interval) + 1
+	}
+
+	n := 0
+	for slot := c.next(from); n < maxSlotCount && !slot.IsZero() && slot.Before(to); slot = c.cron.Next(slot) {
+		n++
+	}
+	return n
+}
+
+// startingDeadline parses the agent's startingDeadline (zero if unset).
+func startingDeadline(agent *corev1alpha1.LegatorAgent) (time.Duration, error) {
+	raw := agent.Spec.Schedule.StartingDeadline
+	if raw == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid startingDeadline %q: %w", raw, err)
+	}
+	return d, nil
+}
+
+// catchUpPolicy returns the agent's catch-up policy (default once).
+func catchUpPolicy(agent *corev1alpha1.LegatorAgent) corev1alpha1.CatchUpPolicy {
+	if p := agent.Spec.Schedule.CatchUp; p != "" {
+		return p
+	}
+	return corev1alpha1.CatchUpOnce
+}
+
+// --- Cron (Step 3.1) ---
+
+// nextCronRun parses a cron expression and returns the next fire time after now.
+func nextCronRun(expr string, now time.Time) (time.Time, error) {
+	sched, err := parseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(now), nil
+}
+
+// parseCron parses a standard five-field cron expression.
+func parseCron(expr string) (cron.Schedule, error) {
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	sched, err := parser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return sched, nil
 }
 
 // --- Interval (Step 3.3) ---
@@ -12,13 +12,18 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
@@ -38,6 +43,8 @@ type Scheduler struct {
 	runner  *runner.Runner
 	tracker *RunTracker
 	webhook *WebhookHandler
+	events  *EventSource
+	queue   *TriggerQueue
 	log     logr.Logger
 
 	// checkInterval is how often the scheduler scans for due agents.
@@ -48,6 +55,11 @@ type Scheduler struct {
 	// Default: 10.
 	maxConcurrentRuns int
 
+	// maxConcurrentPerAgent is the default per-agent limit for agents with
+	// the Allow concurrency policy and no maxConcurrentRuns.
+	// Default: 1.
+	maxConcurrentPerAgent int
+
 	// jitterPercent is the jitter applied to scheduled times.
 	// Default: 10%.
 	jitterPercent float64
@@ -58,23 +70,35 @@ type Scheduler struct {
 	// runConfigFactory builds RunConfig for an agent.
 	// Must be set before Start().
 	RunConfigFactory func(agent *corev1alpha1.LegatorAgent) (runner.RunConfig, error)
+
+	// EventInformers provides the core/v1 Event informer for kubernetes-event
+	// triggers (typically the manager's cache). Optional — if nil, those
+	// triggers are registered but never fire. The informer is only started
+	// once an agent registers such a trigger.
+	EventInformers cache.Informers
+
+	// eventInformerStarted is set once the Event handler is registered.
+	// Only accessed from the Start loop.
+	eventInformerStarted bool
 }
 
 // Config configures the scheduler.
 type Config struct {
-	CheckInterval     time.Duration
-	MaxConcurrentRuns int
-	JitterPercent     float64
-	WebhookDebounce   time.Duration
+	CheckInterval         time.Duration
+	MaxConcurrentRuns     int
+	MaxConcurrentPerAgent int
+	JitterPercent         float64
+	WebhookDebounce       time.Duration
 }
 
 // DefaultConfig returns sensible defaults.
 func DefaultConfig() Config {
 	return Config{
-		CheckInterval:     10 * time.Second,
-		MaxConcurrentRuns: 10,
-		JitterPercent:     10.0,
-		WebhookDebounce:   30 * time.Second,
+		CheckInterval:         10 * time.Second,
+		MaxConcurrentRuns:     10,
+		MaxConcurrentPerAgent: 1,
+		JitterPercent:         10.0,
+		WebhookDebounce:       30 * time.Second,
 	}
 }
 
@@ -86,19 +110,26 @@ func New(c client.Client, r *runner.Runner, log logr.Logger, cfg Config) *Schedu
 	if cfg.MaxConcurrentRuns <= 0 {
 		cfg.MaxConcurrentRuns = 10
 	}
+	if cfg.MaxConcurrentPerAgent <= 0 {
+		cfg.MaxConcurrentPerAgent = 1
+	}
 	if cfg.JitterPercent <= 0 {
 		cfg.JitterPercent = 10.0
 	}
 
+	queue := NewTriggerQueue(c, log.WithName("queue"))
 	return &Scheduler{
-		client:            c,
-		runner:            r,
-		tracker:           NewRunTracker(),
-		webhook:           NewWebhookHandler(log.WithName("webhook"), cfg.WebhookDebounce),
-		log:               log.WithName("scheduler"),
-		checkInterval:     cfg.CheckInterval,
-		maxConcurrentRuns: cfg.MaxConcurrentRuns,
-		jitterPercent:     cfg.JitterPercent,
+		client:                c,
+		runner:                r,
+		tracker:               NewRunTracker(),
+		webhook:               NewWebhookHandler(log.WithName("webhook"), cfg.WebhookDebounce).WithSecrets(c).WithQueue(queue),
+		events:                NewEventSource(log.WithName("events"), cfg.WebhookDebounce).WithQueue(queue),
+		queue:                 queue,
+		log:                   log.WithName("scheduler"),
+		checkInterval:         cfg.CheckInterval,
+		maxConcurrentRuns:     cfg.MaxConcurrentRuns,
+		maxConcurrentPerAgent: cfg.MaxConcurrentPerAgent,
+		jitterPercent:         cfg.JitterPercent,
 	}
 }
 
@@ -124,7 +155,8 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	ticker := time.NewTicker(s.checkInterval)
 	defer ticker.Stop()
 
-	// Also drain webhook triggers
+	// Also drain queued triggers, and firing Alertmanager notifications when
+	// there is no queue
 	for {
 		select {
 		case <-ctx.Done():
@@ -136,6 +168,15 @@ func (s *Scheduler) Start(ctx context.Context) error {
 
 		case trigger := <-s.webhook.Triggers():
 			s.handleWebhookTrigger(ctx, trigger)
+
+		case trigger := <-s.events.Triggers():
+			s.handleEventTrigger(ctx, trigger)
+
+		case trigger := <-s.webhook.Alerts():
+			s.handleAlertTrigger(ctx, trigger)
+
+		case <-s.queue.Wake():
+			s.queue.Drain(ctx, s.dispatchRunRequest)
 		}
 	}
 }
@@ -146,6 +187,14 @@ func (s *Scheduler) tick(ctx context.Context) {
 	if cleaned := s.tracker.CleanStale(30 * time.Minute); cleaned > 0 {
 		s.log.Info("Cleaned stale in-flight runs", "count", cleaned)
 	}
+	s.webhook.groups.Clean()
+
+	// Retry deferred triggers, and pick up any queued by other replicas
+	// or before a restart
+	s.queue.Drain(ctx, s.dispatchRunRequest)
+
+	// Start watching Events once the first kubernetes-event trigger is registered
+	s.ensureEventInformer(ctx)
 
 	// List all LegatorAgents
 	agentList := &corev1alpha1.LegatorAgentList{}
@@ -175,8 +224,10 @@ func (s *Scheduler) evaluateAgent(ctx context.Context, agent *corev1alpha1.Legat
 
 	agentKey := fmt.Sprintf("%s/%s", agent.Namespace, agent.Name)
 
-	// Step 3.5: Concurrency — one run at a time per agent
-	if s.tracker.IsRunning(agentKey) {
+	// Step 3.5: Concurrency — per the agent's concurrencyPolicy. Replace
+	// proceeds and aborts the in-flight run once the new one is due.
+	if concurrencyPolicy(agent) != corev1alpha1.ConcurrencyReplace &&
+		s.tracker.RunCount(agentKey) >= concurrencyLimit(agent, s.maxConcurrentPerAgent) {
 		return
 	}
 
@@ -189,14 +240,25 @@ func (s *Scheduler) evaluateAgent(ctx context.Context, agent *corev1alpha1.Legat
 		return
 	}
 
+	// Slots are recorded in memory when started or skipped: lastRunTime
+	// lags the run, and under Replace a slot seen again would abort the run
+	// it started
+	lastSlot, _ := s.tracker.LastSlot(agentKey)
+
 	// Check if due
-	due, err := IsDue(agent, now)
+	decision, err := EvaluateSchedule(agent, lastSlot, now)
 	if err != nil {
 		s.log.Error(err, "Failed to check schedule", "agent", agent.Name)
 		return
 	}
 
-	if !due {
+	if !decision.Due {
+		if decision.Missed > 0 {
+			// Every missed slot is past its deadline — skip them
+			s.tracker.RecordSlot(agentKey, decision.Slot)
+			s.recordMissedSchedule(ctx, agent, decision)
+			return
+		}
 		// Step 3.9: Update next run time on status
 		s.updateNextRunTime(ctx, agent, now)
 		return
@@ -211,34 +273,115 @@ func (s *Scheduler) evaluateAgent(ctx context.Context, agent *corev1alpha1.Legat
 	}
 
 	// Trigger the run
-	s.triggerRun(ctx, agent, agentKey, corev1alpha1.RunTriggerScheduled)
+	if err := s.triggerRun(ctx, agent, agentKey, corev1alpha1.RunTriggerScheduled, nil); err != nil {
+		return
+	}
+	s.tracker.RecordSlot(agentKey, decision.Slot)
+	s.recordMissedSchedule(ctx, agent, decision)
+}
+
+// recordMissedSchedule records the slots a schedule decision skipped or
+// still owes as the MissedSchedule condition.
+func (s *Scheduler) recordMissedSchedule(ctx context.Context, agent *corev1alpha1.LegatorAgent, decision ScheduleDecision) {
+	// Refetch to avoid conflicts (the run goroutine holds agent)
+	fresh := &corev1alpha1.LegatorAgent{}
+	key := types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}
+	if err := s.client.Get(ctx, key, fresh); err != nil {
+		s.log.Error(err, "Failed to refetch agent to record missed schedule", "agent", agent.Name)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:               "MissedSchedule",
+		Status:             metav1.ConditionFalse,
+		Reason:             "OnSchedule",
+		Message:            "No missed runs",
+		ObservedGeneration: fresh.Generation,
+	}
+	slot := decision.Slot.Format(time.RFC3339)
+	switch {
+	case !decision.Due:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "StartingDeadlineExceeded"
+		cond.Message = fmt.Sprintf("Skipped %d missed run(s) through %s: past startingDeadline %q",
+			decision.Missed, slot, fresh.Spec.Schedule.StartingDeadline)
+	case decision.Remaining > 0:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "CatchingUp"
+		cond.Message = fmt.Sprintf("Running missed slot %s; %d more to catch up", slot, decision.Remaining)
+		if decision.Missed > 0 {
+			cond.Message += fmt.Sprintf(", %d skipped past startingDeadline", decision.Missed)
+		}
+	case decision.Missed > 0:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "MissedRunsSkipped"
+		cond.Message = fmt.Sprintf("Skipped %d missed run(s) before %s (catchUp %s)",
+			decision.Missed, slot, catchUpPolicy(fresh))
+	}
+	meta.SetStatusCondition(&fresh.Status.Conditions, cond)
+
+	if cond.Status == metav1.ConditionTrue {
+		s.log.Info("Missed scheduled runs",
+			"agent", agent.Name,
+			"reason", cond.Reason,
+			"missed", decision.Missed,
+			"remaining", decision.Remaining,
+		)
+	}
+
+	if err := s.client.Status().Update(ctx, fresh); err != nil {
+		s.log.Error(err, "Failed to record missed schedule", "agent", agent.Name)
+	}
 }
 
+// errAgentRunning means the agent already has a run in flight.
+var errAgentRunning = errors.New("agent already running")
+
 // triggerRun starts an agent run in a goroutine with concurrency tracking.
+// It returns an error if the run could not be started now. Under the Replace
+// concurrency policy the agent's in-flight runs are aborted first.
 func (s *Scheduler) triggerRun(
 	ctx context.Context,
 	agent *corev1alpha1.LegatorAgent,
 	agentKey string,
 	trigger corev1alpha1.RunTrigger,
-) {
+	triggerCtx *corev1alpha1.TriggerContext,
+) error {
+	policy := concurrencyPolicy(agent)
+	limit := concurrencyLimit(agent, s.maxConcurrentPerAgent)
+	replacing := policy == corev1alpha1.ConcurrencyReplace && s.tracker.IsRunning(agentKey)
+	if replacing {
+		// The replaced run stays tracked until its goroutine exits.
+		limit = 0
+	}
+
 	// Rate limit check (if limiter configured)
 	if s.RateLimiter != nil {
-		isWebhook := trigger == corev1alpha1.RunTriggerWebhook
-		decision := s.RateLimiter.Allow(agentKey, isWebhook)
+		// Event-driven runs get the webhook burst allowance
+		isWebhook := trigger == corev1alpha1.RunTriggerWebhook || trigger == corev1alpha1.RunTriggerEvent
+		decision := s.RateLimiter.AllowConcurrent(agentKey, isWebhook, limit)
 		if !decision.Allowed {
 			s.log.Info("Agent run rate-limited",
 				"agent", agent.Name,
 				"reason", decision.Reason,
 			)
-			return
+			return fmt.Errorf("rate limited: %s", decision.Reason)
 		}
 	}
 
 	runName := fmt.Sprintf("%s-run", agent.Name)
 
-	if !s.tracker.TryStart(agentKey, runName) {
-		s.log.Info("Agent already running, skipping", "agent", agent.Name)
-		return
+	// Tracked runs can be aborted before they reach the runner; cancel via
+	// the tracker or Runner.Abort
+	runCtx, cancelRun := context.WithCancelCause(context.Background())
+	runID, ok := s.tracker.StartWithCancel(agentKey, runName, limit, cancelRun)
+	if !ok {
+		cancelRun(nil)
+		s.log.Info("Agent already running, skipping",
+			"agent", agent.Name,
+			"concurrencyPolicy", policy,
+		)
+		return errAgentRunning
 	}
 
 	// Record rate limiter start
@@ -259,25 +402,35 @@ func (s *Scheduler) triggerRun(
 		cfg, err = s.RunConfigFactory(agent)
 		if err != nil {
 			s.log.Error(err, "Failed to create run config", "agent", agent.Name)
-			s.tracker.Complete(agentKey)
+			cancelRun(nil)
+			s.tracker.Finish(agentKey, runID)
 			if s.RateLimiter != nil {
 				s.RateLimiter.RecordComplete(agentKey)
 			}
-			return
+			return fmt.Errorf("run config: %w", err)
 		}
 	}
 	cfg.Trigger = trigger
+	cfg.TriggerContext = triggerCtx
+
+	if replacing {
+		aborted := s.tracker.Abort(agentKey, runID, &runner.AbortError{By: "concurrency-policy:Replace"})
+		s.log.Info("Replacing in-flight agent run",
+			"agent", agent.Name,
+			"aborted", aborted,
+		)
+	}
 
 	// Run in goroutine (non-blocking)
 	go func() {
-		defer s.tracker.Complete(agentKey)
+		defer cancelRun(nil)
+		defer s.tracker.Finish(agentKey, runID)
 		defer func() {
 			if s.RateLimiter != nil {
 				s.RateLimiter.RecordComplete(agentKey)
 			}
 		}()
 
-		runCtx := context.Background() // Independent of scheduler tick context
 		agentRun, err := s.runner.Execute(runCtx, agent, cfg)
 		if err != nil {
 			s.log.Error(err, "Agent run failed",
@@ -295,32 +448,77 @@ func (s *Scheduler) triggerRun(
 		// Update agent status after run
 		s.updateAgentAfterRun(context.Background(), agent, agentRun)
 	}()
+	return nil
 }
 
 // handleWebhookTrigger processes a webhook-initiated trigger.
 func (s *Scheduler) handleWebhookTrigger(ctx context.Context, trigger WebhookTrigger) {
-	agentKey := fmt.Sprintf("%s/%s", trigger.AgentKey.Namespace, trigger.AgentKey.Name)
+	_ = s.handleReactiveTrigger(ctx, trigger.AgentKey, trigger.Source,
+		corev1alpha1.RunTriggerWebhook, trigger.TriggerContext())
+}
+
+// handleEventTrigger processes a Kubernetes Event-initiated trigger.
+func (s *Scheduler) handleEventTrigger(ctx context.Context, trigger EventTrigger) {
+	_ = s.handleReactiveTrigger(ctx, trigger.AgentKey, string(corev1alpha1.TriggerKubernetesEvent),
+		corev1alpha1.RunTriggerEvent, trigger.TriggerContext())
+}
+
+// handleAlertTrigger starts a run for a firing Alertmanager group.
+func (s *Scheduler) handleAlertTrigger(ctx context.Context, trigger AlertTrigger) {
+	_ = s.handleReactiveTrigger(ctx, trigger.AgentKey, trigger.Source,
+		corev1alpha1.RunTriggerWebhook, trigger.TriggerContext())
+}
+
+// dispatchRunRequest starts the run for a queued trigger.
+func (s *Scheduler) dispatchRunRequest(ctx context.Context, rr *corev1alpha1.RunRequest) error {
+	source := string(rr.Spec.Trigger)
+	if rr.Spec.TriggerContext != nil && rr.Spec.TriggerContext.Source != "" {
+		source = rr.Spec.TriggerContext.Source
+	}
+	key := types.NamespacedName{Namespace: rr.Namespace, Name: rr.Spec.AgentRef}
+	return s.handleReactiveTrigger(ctx, key, source, rr.Spec.Trigger, rr.Spec.TriggerContext)
+}
+
+// handleReactiveTrigger starts a run for an externally-triggered agent,
+// carrying the triggering payload into the run context. It returns nil once
+// the trigger is handled (including when the agent is gone or paused), or
+// an error if it should be retried.
+func (s *Scheduler) handleReactiveTrigger(
+	ctx context.Context,
+	key types.NamespacedName,
+	source string,
+	runTrigger corev1alpha1.RunTrigger,
+	triggerCtx *corev1alpha1.TriggerContext,
+) error {
+	agentKey := fmt.Sprintf("%s/%s", key.Namespace, key.Name)
 
 	// Fetch fresh agent state
 	agent := &corev1alpha1.LegatorAgent{}
-	if err := s.client.Get(ctx, trigger.AgentKey, agent); err != nil {
-		s.log.Error(err, "Failed to get agent for webhook trigger",
-			"agent", trigger.AgentKey.String(),
-			"source", trigger.Source,
+	if err := s.client.Get(ctx, key, agent); err != nil {
+		if apierrors.IsNotFound(err) {
+			s.log.Info("Trigger ignored — agent not found",
+				"agent", key.String(),
+				"source", source,
+			)
+			return nil
+		}
+		s.log.Error(err, "Failed to get agent for trigger",
+			"agent", key.String(),
+			"source", source,
 		)
-		return
+		return err
 	}
 
 	// Respect pause
 	if agent.Spec.Paused {
-		s.log.Info("Webhook trigger ignored — agent paused",
+		s.log.Info("Trigger ignored — agent paused",
 			"agent", agent.Name,
-			"source", trigger.Source,
+			"source", source,
 		)
-		return
+		return nil
 	}
 
-	s.triggerRun(ctx, agent, agentKey, corev1alpha1.RunTriggerWebhook)
+	return s.triggerRun(ctx, agent, agentKey, runTrigger, triggerCtx)
 }
 
 // updateNextRunTime computes and updates the next scheduled run time on status.
@@ -397,22 +595,124 @@ func (s *Scheduler) NeedLeaderElection() bool {
 	return true
 }
 
-// RegisterWebhookTriggers scans an agent's trigger list and registers
-// webhook sources with the webhook handler.
-func (s *Scheduler) RegisterWebhookTriggers(agent *corev1alpha1.LegatorAgent) {
+// RegisterTriggers scans an agent's trigger list and registers webhook
+// sources with the webhook handler and kubernetes-event triggers with the
+// event source. Event triggers are scoped to the namespaces of the agent's
+// LegatorEnvironment when it declares any.
+func (s *Scheduler) RegisterTriggers(agent *corev1alpha1.LegatorAgent) {
 	agentKey := types.NamespacedName{Namespace: agent.Namespace, Name: agent.Name}
 
 	// Clear existing registrations
 	s.webhook.UnregisterAgent(agentKey)
+	s.events.UnregisterAgent(agentKey)
 
 	// Register new ones
+	var namespaces []string
+	namespacesResolved := false
 	for _, trigger := range agent.Spec.Schedule.Triggers {
-		if trigger.Type == corev1alpha1.TriggerWebhook && trigger.Source != "" {
-			s.webhook.RegisterAgent(trigger.Source, agentKey)
+		switch trigger.Type {
+		case corev1alpha1.TriggerWebhook, corev1alpha1.TriggerAlertmanager:
+			source := trigger.Source
+			if source == "" {
+				if trigger.Type != corev1alpha1.TriggerAlertmanager {
+					continue
+				}
+				source = string(corev1alpha1.TriggerAlertmanager)
+			}
+			var filter *PayloadFilter
+			if trigger.Filter != "" {
+				var err error
+				filter, err = CompileFilter(trigger.Filter)
+				if err != nil {
+					// Fail closed: an invalid filter must not fire on every payload.
+					// The agent controller reports the error as a status condition.
+					s.log.Error(err, "Skipping webhook trigger with invalid filter",
+						"agent", agent.Name,
+						"source", source,
+					)
+					continue
+				}
+			}
+			if trigger.Type == corev1alpha1.TriggerAlertmanager {
+				s.webhook.RegisterAlertmanagerAgent(source, agentKey, trigger.Auth, filter)
+			} else {
+				s.webhook.RegisterAgentWithAuth(source, agentKey, trigger.Auth, filter)
+			}
 			s.log.Info("Registered webhook trigger",
 				"agent", agent.Name,
-				"source", trigger.Source,
+				"type", trigger.Type,
+				"source", source,
+				"authenticated", trigger.Auth != nil,
+				"filtered", filter != nil,
+			)
+
+		case corev1alpha1.TriggerKubernetesEvent:
+			if !namespacesResolved {
+				namespaces = s.environmentNamespaces(agent)
+				namespacesResolved = true
+			}
+			s.events.RegisterAgent(agentKey, trigger, namespaces)
+			s.log.Info("Registered kubernetes-event trigger",
+				"agent", agent.Name,
+				"resources", trigger.Resources,
+				"reasons", trigger.Reasons,
+				"namespaces", namespaces,
 			)
 		}
 	}
 }
+
+// environmentNamespaces returns the namespaces declared by the agent's
+// LegatorEnvironment, or nil (all namespaces) if it declares none or
+// cannot be read.
+func (s *Scheduler) environmentNamespaces(agent *corev1alpha1.LegatorAgent) []string {
+	if agent.Spec.EnvironmentRef == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	env := &corev1alpha1.LegatorEnvironment{}
+	key := types.NamespacedName{Namespace: agent.Namespace, Name: agent.Spec.EnvironmentRef}
+	if err := s.client.Get(ctx, key, env); err != nil {
+		s.log.Error(err, "Failed to read environment for event trigger scope — watching all namespaces",
+			"agent", agent.Name,
+			"environment", agent.Spec.EnvironmentRef,
+		)
+		return nil
+	}
+	return environmentNamespaces(env.Spec.Namespaces)
+}
+
+// ensureEventInformer registers the Event handler with the informer the
+// first time a kubernetes-event trigger exists, so clusters that don't use
+// event triggers never watch Events.
+func (s *Scheduler) ensureEventInformer(ctx context.Context) {
+	if s.eventInformerStarted || s.EventInformers == nil || !s.events.HasRegistrations() {
+		return
+	}
+
+	informer, err := s.EventInformers.GetInformer(ctx, &corev1.Event{})
+	if err != nil {
+		s.log.Error(err, "Failed to get Event informer — kubernetes-event triggers inactive")
+		return
+	}
+
+	handle := func(obj interface{}) {
+		if ev, ok := obj.(*corev1.Event); ok {
+			s.events.Handle(ev)
+		}
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    handle,
+		UpdateFunc: func(_, obj interface{}) { handle(obj) },
+	}); err != nil {
+		s.log.Error(err, "Failed to add Event handler — kubernetes-event triggers inactive")
+		return
+	}
+
+	go s.events.Run(ctx)
+	s.eventInformerStarted = true
+	s.log.Info("Watching Kubernetes events for kubernetes-event triggers")
+}
@@ -11,18 +11,25 @@ You may obtain a copy of the License at
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/assembler"
+	"github.com/marcus-qen/legator/internal/runner"
 )
 
 func init() {
@@ -317,6 +324,196 @@ func TestIsDue_Paused(t *testing.T) {
 	}
 }
 
+func catchUpAgent(catchUp corev1alpha1.CatchUpPolicy, deadline string, lastRun time.Time) *corev1alpha1.LegatorAgent {
+	return &corev1alpha1.LegatorAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"},
+		Spec: corev1alpha1.LegatorAgentSpec{
+			Schedule: corev1alpha1.ScheduleSpec{
+				Cron:             "*/5 * * * *",
+				CatchUp:          catchUp,
+				StartingDeadline: deadline,
+			},
+		},
+		Status: corev1alpha1.LegatorAgentStatus{
+			LastRunTime: &metav1.Time{Time: lastRun},
+		},
+	}
+}
+
+func TestEvaluateSchedule_CatchUp(t *testing.T) {
+	lastRun := time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 2, 19, 10, 23, 0, 0, time.UTC) // missed :05, :10, :15, :20
+	at := func(min int) time.Time { return time.Date(2026, 2, 19, 10, min, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		catchUp  corev1alpha1.CatchUpPolicy
+		deadline string
+		want     ScheduleDecision
+	}{
+		{"once runs latest", corev1alpha1.CatchUpOnce, "", ScheduleDecision{Due: true, Slot: at(20), Missed: 3}},
+		{"default is once", "", "", ScheduleDecision{Due: true, Slot: at(20), Missed: 3}},
+		{"once past deadline", corev1alpha1.CatchUpOnce, "2m", ScheduleDecision{Slot: at(20), Missed: 4}},
+		{"none skips late slots", corev1alpha1.CatchUpNone, "", ScheduleDecision{Slot: at(20), Missed: 4}},
+		{"none within deadline", corev1alpha1.CatchUpNone, "5m", ScheduleDecision{Due: true, Slot: at(20), Missed: 3}},
+		{"all runs oldest", corev1alpha1.CatchUpAll, "", ScheduleDecision{Due: true, Slot: at(5), Remaining: 3}},
+		{"all within deadline", corev1alpha1.CatchUpAll, "10m", ScheduleDecision{Due: true, Slot: at(15), Missed: 2, Remaining: 1}},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateSchedule(catchUpAgent(tt.catchUp, tt.deadline, lastRun), time.Time{}, now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateSchedule_AllResumesFromLastSlot(t *testing.T) {
+	// The catch-up run for :05 finished at :22; :10, :15 and :20 are still owed
+	agent := catchUpAgent(corev1alpha1.CatchUpAll, "", time.Date(2026, 2, 19, 10, 22, 0, 0, time.UTC))
+	lastSlot := time.Date(2026, 2, 19, 10, 5, 0, 0, time.UTC)
+
+	got, err := EvaluateSchedule(agent, lastSlot, time.Date(2026, 2, 19, 10, 23, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ScheduleDecision{Due: true, Slot: time.Date(2026, 2, 19, 10, 10, 0, 0, time.UTC), Remaining: 2}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestEvaluateSchedule_LongDowntime(t *testing.T) {
+	// A year of every-minute slots is found without walking them all
+	lastRun := time.Date(2025, 2, 19, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 2, 19, 10, 0, 30, 0, time.UTC)
+	everyMinute := func(catchUp corev1alpha1.CatchUpPolicy, deadline string) *corev1alpha1.LegatorAgent {
+		agent := catchUpAgent(catchUp, deadline, lastRun)
+		agent.Spec.Schedule.Cron = "* * * * *"
+		return agent
+	}
+	interval := catchUpAgent(corev1alpha1.CatchUpNone, "10s", lastRun)
+	interval.Spec.Schedule.Cron = ""
+	interval.Spec.Schedule.Interval = "1m"
+
+	tests := []struct {
+		name  string
+		agent *corev1alpha1.LegatorAgent
+		want  ScheduleDecision
+	}{
+		{"cron once runs latest", everyMinute(corev1alpha1.CatchUpOnce, ""),
+			ScheduleDecision{Due: true, Slot: now.Truncate(time.Minute), Missed: maxSlotCount}},
+		{"cron all within deadline", everyMinute(corev1alpha1.CatchUpAll, "10m"),
+			ScheduleDecision{Due: true, Slot: time.Date(2026, 2, 19, 9, 51, 0, 0, time.UTC), Missed: maxSlotCount, Remaining: 9}},
+		{"interval past deadline", interval,
+			ScheduleDecision{Slot: now.Truncate(time.Minute), Missed: 365 * 24 * 60}},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateSchedule(tt.agent, time.Time{}, now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateSchedule_InvalidDeadline(t *testing.T) {
+	agent := catchUpAgent(corev1alpha1.CatchUpOnce, "soon", time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC))
+	if _, err := EvaluateSchedule(agent, time.Time{}, time.Date(2026, 2, 19, 10, 23, 0, 0, time.UTC)); err == nil {
+		t.Error("expected error for invalid startingDeadline")
+	}
+}
+
+func TestScheduler_RecordsSkippedSlots(t *testing.T) {
+	lastRun := time.Now().Add(-time.Hour)
+	agent := catchUpAgent(corev1alpha1.CatchUpOnce, "1s", lastRun)
+	scheme := runtime.NewScheme()
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&corev1alpha1.LegatorAgent{}).WithObjects(agent).Build()
+	s := New(c, nil, logf.Log.WithName("test"), DefaultConfig())
+
+	s.evaluateAgent(context.Background(), agent.DeepCopy(), time.Now())
+
+	fresh := &corev1alpha1.LegatorAgent{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "agents", Name: "watchman"}, fresh); err != nil {
+		t.Fatal(err)
+	}
+	if slot, ok := s.tracker.LastSlot("agents/watchman"); !ok || !slot.After(lastRun) {
+		t.Errorf("expected the skipped slots to be recorded, got %v", slot)
+	}
+	cond := meta.FindStatusCondition(fresh.Status.Conditions, "MissedSchedule")
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "StartingDeadlineExceeded" {
+		t.Errorf("expected MissedSchedule=True/StartingDeadlineExceeded, got %+v", cond)
+	}
+}
+
+func TestScheduler_ReplaceDoesNotRetriggerStartedSlot(t *testing.T) {
+	agent := &corev1alpha1.LegatorAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"},
+		Spec: corev1alpha1.LegatorAgentSpec{
+			Schedule: corev1alpha1.ScheduleSpec{Interval: "5m", ConcurrencyPolicy: corev1alpha1.ConcurrencyReplace},
+		},
+	}
+	scheme := runtime.NewScheme()
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&corev1alpha1.LegatorAgent{}).WithObjects(agent).Build()
+	s := New(c, nil, logf.Log.WithName("test"), DefaultConfig())
+
+	// A run was started for the agent's first slot, but its status hasn't
+	// caught up: the agent still looks like it has never run
+	s.tracker.Start("agents/watchman", "watchman-run", 1)
+	s.tracker.RecordSlot("agents/watchman", time.Now())
+
+	s.evaluateAgent(context.Background(), agent.DeepCopy(), time.Now().Add(10*time.Second))
+
+	if n := s.tracker.RunCount("agents/watchman"); n != 1 {
+		t.Errorf("expected the in-flight run to be left alone, %d runs in flight", n)
+	}
+}
+
+func TestScheduler_ReplaceAbortsRunNotYetRegistered(t *testing.T) {
+	agent := &corev1alpha1.LegatorAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman", Namespace: "agents"},
+		Spec: corev1alpha1.LegatorAgentSpec{
+			Schedule:       corev1alpha1.ScheduleSpec{Interval: "5m", ConcurrencyPolicy: corev1alpha1.ConcurrencyReplace},
+			EnvironmentRef: "missing",
+		},
+	}
+	scheme := runtime.NewScheme()
+	if err := corev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&corev1alpha1.LegatorAgent{}, &corev1alpha1.LegatorRun{}).WithObjects(agent).Build()
+	r := runner.NewRunner(c, assembler.New(c), logf.Log.WithName("test"))
+	s := New(c, r, logf.Log.WithName("test"), DefaultConfig())
+
+	// A run has passed tracker.Start but hasn't reached the runner, so
+	// Runner.Abort doesn't know about it yet
+	oldCtx, cancelOld := context.WithCancelCause(context.Background())
+	defer cancelOld(nil)
+	s.tracker.StartWithCancel("agents/watchman", "watchman-run", 1, cancelOld)
+
+	if err := s.triggerRun(context.Background(), agent.DeepCopy(), "agents/watchman", corev1alpha1.RunTriggerManual, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var ae *runner.AbortError
+	if !errors.As(context.Cause(oldCtx), &ae) || ae.By != "concurrency-policy:Replace" {
+		t.Errorf("expected the unregistered run to be aborted by Replace, got cause %v", context.Cause(oldCtx))
+	}
+}
+
 // --- Jitter tests (Step 3.4) ---
 
 func TestApplyJitter_Bounded(t *testing.T) {
@@ -447,7 +644,7 @@ func TestRunTracker_CleanStale(t *testing.T) {
 
 	tracker.TryStart("ns/agent1", "run1")
 	// Hack: manually set start time to 1 hour ago
-	tracker.inflight["ns/agent1"].StartedAt = time.Now().Add(-1 * time.Hour)
+	tracker.inflight["ns/agent1"][0].StartedAt = time.Now().Add(-1 * time.Hour)
 
 	cleaned := tracker.CleanStale(30 * time.Minute)
 	if cleaned != 1 {
@@ -458,6 +655,105 @@ func TestRunTracker_CleanStale(t *testing.T) {
 	}
 }
 
+func TestRunTracker_StartWithLimit(t *testing.T) {
+	tracker := NewRunTracker()
+
+	id1, ok := tracker.Start("ns/agent1", "run1", 2)
+	if !ok {
+		t.Fatal("first run should start")
+	}
+	id2, ok := tracker.Start("ns/agent1", "run2", 2)
+	if !ok {
+		t.Fatal("second run should start under limit 2")
+	}
+	if _, ok := tracker.Start("ns/agent1", "run3", 2); ok {
+		t.Error("third run should be refused at limit 2")
+	}
+	if tracker.RunCount("ns/agent1") != 2 || tracker.InFlightCount() != 2 {
+		t.Errorf("expected 2 in flight, got %d (total %d)", tracker.RunCount("ns/agent1"), tracker.InFlightCount())
+	}
+
+	tracker.Finish("ns/agent1", id1)
+	if info := tracker.GetRunInfo("ns/agent1"); info == nil || info.ID != id2 {
+		t.Errorf("expected run %d to remain, got %+v", id2, info)
+	}
+	if _, ok := tracker.Start("ns/agent1", "run3", 2); !ok {
+		t.Error("run should start after one finished")
+	}
+
+	// Unlimited (Replace while the old run drains)
+	if _, ok := tracker.Start("ns/agent1", "run4", 0); !ok {
+		t.Error("limit 0 should be unlimited")
+	}
+}
+
+func TestRunTracker_Abort(t *testing.T) {
+	tracker := NewRunTracker()
+	ctx1, cancel1 := context.WithCancelCause(context.Background())
+	defer cancel1(nil)
+	ctx2, cancel2 := context.WithCancelCause(context.Background())
+	defer cancel2(nil)
+
+	tracker.StartWithCancel("ns/agent1", "run1", 0, cancel1)
+	id2, _ := tracker.StartWithCancel("ns/agent1", "run2", 0, cancel2)
+	tracker.Start("ns/agent1", "run3", 0)
+
+	cause := errors.New("replaced")
+	if n := tracker.Abort("ns/agent1", id2, cause); n != 1 {
+		t.Errorf("expected 1 run aborted, got %d", n)
+	}
+	if context.Cause(ctx1) != cause {
+		t.Errorf("expected run1 to be cancelled with the cause, got %v", context.Cause(ctx1))
+	}
+	if ctx2.Err() != nil {
+		t.Error("the kept run should not be cancelled")
+	}
+	if tracker.RunCount("ns/agent1") != 3 {
+		t.Error("aborted runs stay tracked until they finish")
+	}
+}
+
+func TestRunTracker_RecordSlot(t *testing.T) {
+	tracker := NewRunTracker()
+	if _, ok := tracker.LastSlot("ns/agent1"); ok {
+		t.Error("expected no slot before a run starts")
+	}
+
+	slot := time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC)
+	tracker.RecordSlot("ns/agent1", slot)
+	tracker.RecordSlot("ns/agent1", slot.Add(-time.Hour))
+	if got, ok := tracker.LastSlot("ns/agent1"); !ok || !got.Equal(slot) {
+		t.Errorf("expected the latest slot %v, got %v", slot, got)
+	}
+
+	// Slots outlive the runs they started
+	tracker.Complete("ns/agent1")
+	if _, ok := tracker.LastSlot("ns/agent1"); !ok {
+		t.Error("expected the slot to be kept after the run finished")
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	three := int32(3)
+	tests := []struct {
+		name     string
+		schedule corev1alpha1.ScheduleSpec
+		want     int
+	}{
+		{"default is Forbid", corev1alpha1.ScheduleSpec{}, 1},
+		{"Forbid", corev1alpha1.ScheduleSpec{ConcurrencyPolicy: corev1alpha1.ConcurrencyForbid, MaxConcurrentRuns: &three}, 1},
+		{"Replace", corev1alpha1.ScheduleSpec{ConcurrencyPolicy: corev1alpha1.ConcurrencyReplace}, 1},
+		{"Allow uses controller default", corev1alpha1.ScheduleSpec{ConcurrencyPolicy: corev1alpha1.ConcurrencyAllow}, 2},
+		{"Allow with maxConcurrentRuns", corev1alpha1.ScheduleSpec{ConcurrencyPolicy: corev1alpha1.ConcurrencyAllow, MaxConcurrentRuns: &three}, 3},
+	}
+	for _, tt := range tests {
+		agent := &corev1alpha1.LegatorAgent{Spec: corev1alpha1.LegatorAgentSpec{Schedule: tt.schedule}}
+		if got := concurrencyLimit(agent, 2); got != tt.want {
+			t.Errorf("%s: concurrencyLimit = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
 // --- Debouncer tests (Step 3.8) ---
 
 func TestDebouncer_FirstAlwaysFires(t *testing.T) {
@@ -641,6 +937,39 @@ func TestWebhookHandler_UnregisterAgent(t *testing.T) {
 
 // --- extractSource tests ---
 
+func TestWebhookTrigger_TriggerContext(t *testing.T) {
+	trigger := WebhookTrigger{
+		Source:  "alertmanager",
+		Payload: `{"alerts":[{"labels":{"alertname":"NodeDown"}}],"auth":"Bearer eyJhbGciOiJIUzI1NiJ9.secret"}`,
+	}
+
+	tc := trigger.TriggerContext()
+	if tc.Source != "alertmanager" {
+		t.Errorf("expected source 'alertmanager', got %q", tc.Source)
+	}
+	if !strings.Contains(tc.Payload, "NodeDown") {
+		t.Errorf("payload lost alert content: %s", tc.Payload)
+	}
+	if strings.Contains(tc.Payload, "eyJhbGciOiJIUzI1NiJ9") {
+		t.Errorf("payload not sanitized: %s", tc.Payload)
+	}
+}
+
+func TestWebhookTrigger_TriggerContextCapped(t *testing.T) {
+	trigger := WebhookTrigger{
+		Source:  "generic",
+		Payload: strings.Repeat("x", 4*maxTriggerPayloadBytes),
+	}
+
+	tc := trigger.TriggerContext()
+	if len(tc.Payload) > maxTriggerPayloadBytes+len("... (truncated)") {
+		t.Errorf("payload not capped: %d bytes", len(tc.Payload))
+	}
+	if !strings.HasSuffix(tc.Payload, "(truncated)") {
+		t.Error("expected truncation marker on capped payload")
+	}
+}
+
 func TestExtractSource(t *testing.T) {
 	tests := []struct {
 		path string
@@ -11,7 +11,9 @@ You may obtain a copy of the License at
 package scheduler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,8 +23,21 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/events"
+	"github.com/marcus-qen/legator/internal/metrics"
+	"github.com/marcus-qen/legator/internal/security"
 )
 
+// errTriggerChannelFull means the in-memory trigger channel had no room.
+var errTriggerChannelFull = errors.New("trigger channel full")
+
+// maxTriggerPayloadBytes caps how much of a webhook payload reaches the agent
+// prompt and the LegatorRun audit record.
+const maxTriggerPayloadBytes = 8 * 1024
+
 // WebhookHandler receives HTTP requests and triggers agent runs.
 // It supports generic webhooks and Alertmanager-formatted payloads.
 type WebhookHandler struct {
@@ -31,9 +46,40 @@ type WebhookHandler struct {
 	debouncer *Debouncer
 	triggers  chan WebhookTrigger
 
-	// agentMap maps source names to agent NamespacedNames.
+	// alerts carries notifications for alertmanager triggers, deduped by
+	// groupKey in groups rather than by the debouncer.
+	alerts chan AlertTrigger
+	groups *alertGroups
+
+	// agentMap maps source names to registered agents.
 	// Populated from LegatorAgent trigger specs.
-	agentMap map[string][]types.NamespacedName
+	agentMap map[string][]webhookRegistration
+
+	// secrets reads the Secrets referenced by trigger auth specs.
+	secrets client.Reader
+
+	// queue persists run triggers. Optional — if nil, triggers are handed
+	// to the scheduler over the in-memory channels.
+	queue *TriggerQueue
+
+	// bus publishes AgentEvents for resolved Alertmanager groups. Optional —
+	// if nil, resolved notifications are only logged.
+	bus *events.Bus
+}
+
+// webhookRegistration is one agent's webhook trigger for a source.
+type webhookRegistration struct {
+	agentKey types.NamespacedName
+
+	// auth is nil for unauthenticated triggers.
+	auth *corev1alpha1.WebhookAuthSpec
+
+	// filter is nil when every payload matches.
+	filter *PayloadFilter
+
+	// alertmanager registrations parse the body as an Alertmanager
+	// notification and emit AlertTriggers.
+	alertmanager bool
 }
 
 // WebhookTrigger is emitted when a webhook fires for an agent.
@@ -44,38 +90,90 @@ type WebhookTrigger struct {
 	Time     time.Time
 }
 
+// TriggerContext returns the run's trigger context: the payload with secrets
+// redacted and truncated to maxTriggerPayloadBytes.
+func (t WebhookTrigger) TriggerContext() *corev1alpha1.TriggerContext {
+	return &corev1alpha1.TriggerContext{
+		Source:  t.Source,
+		Payload: security.SanitizeActionResult(t.Payload, maxTriggerPayloadBytes),
+	}
+}
+
 // NewWebhookHandler creates a webhook handler.
 func NewWebhookHandler(log logr.Logger, debounceWindow time.Duration) *WebhookHandler {
 	return &WebhookHandler{
 		log:       log,
 		debouncer: NewDebouncer(debounceWindow),
 		triggers:  make(chan WebhookTrigger, 100),
-		agentMap:  make(map[string][]types.NamespacedName),
+		alerts:    make(chan AlertTrigger, 100),
+		groups:    newAlertGroups(defaultAlertGroupTTL),
+		agentMap:  make(map[string][]webhookRegistration),
 	}
 }
 
+// WithSecrets sets the reader used to resolve webhook auth Secrets.
+func (h *WebhookHandler) WithSecrets(r client.Reader) *WebhookHandler {
+	h.secrets = r
+	return h
+}
+
+// WithQueue sets the durable queue that run triggers are written to.
+func (h *WebhookHandler) WithQueue(q *TriggerQueue) *WebhookHandler {
+	h.queue = q
+	return h
+}
+
+// WithEventBus sets the bus resolved Alertmanager groups are published on.
+func (h *WebhookHandler) WithEventBus(b *events.Bus) *WebhookHandler {
+	h.bus = b
+	return h
+}
+
 // Triggers returns the channel of webhook trigger events.
 // The scheduler reads from this to initiate agent runs.
 func (h *WebhookHandler) Triggers() <-chan WebhookTrigger {
 	return h.triggers
 }
 
-// RegisterAgent adds a mapping from a source name to an agent.
+// Alerts returns the channel of firing Alertmanager triggers, used when no
+// queue is configured.
+func (h *WebhookHandler) Alerts() <-chan AlertTrigger {
+	return h.alerts
+}
+
+// RegisterAgent adds an unauthenticated, unfiltered mapping from a source name to an agent.
 func (h *WebhookHandler) RegisterAgent(source string, agentKey types.NamespacedName) {
+	h.RegisterAgentWithAuth(source, agentKey, nil, nil)
+}
+
+// RegisterAgentWithAuth adds a mapping from a source name to an agent. If auth
+// is non-nil, requests must authenticate against it to trigger the agent. If
+// filter is non-nil, only payloads it matches trigger the agent.
+func (h *WebhookHandler) RegisterAgentWithAuth(source string, agentKey types.NamespacedName, auth *corev1alpha1.WebhookAuthSpec, filter *PayloadFilter) {
+	h.register(source, webhookRegistration{agentKey: agentKey, auth: auth, filter: filter})
+}
+
+// RegisterAlertmanagerAgent adds an Alertmanager mapping from a source name to
+// an agent. Auth and filter behave as for RegisterAgentWithAuth.
+func (h *WebhookHandler) RegisterAlertmanagerAgent(source string, agentKey types.NamespacedName, auth *corev1alpha1.WebhookAuthSpec, filter *PayloadFilter) {
+	h.register(source, webhookRegistration{agentKey: agentKey, auth: auth, filter: filter, alertmanager: true})
+}
+
+func (h *WebhookHandler) register(source string, reg webhookRegistration) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.agentMap[source] = append(h.agentMap[source], agentKey)
+	h.agentMap[source] = append(h.agentMap[source], reg)
 }
 
 // UnregisterAgent removes all mappings for an agent.
 func (h *WebhookHandler) UnregisterAgent(agentKey types.NamespacedName) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	for source, agents := range h.agentMap {
-		var filtered []types.NamespacedName
-		for _, a := range agents {
-			if a != agentKey {
-				filtered = append(filtered, a)
+	for source, regs := range h.agentMap {
+		var filtered []webhookRegistration
+		for _, reg := range regs {
+			if reg.agentKey != agentKey {
+				filtered = append(filtered, reg)
 			}
 		}
 		if len(filtered) == 0 {
@@ -90,6 +188,11 @@ func (h *WebhookHandler) UnregisterAgent(agentKey types.NamespacedName) {
 // Routes:
 //   - POST /webhook/{source} — generic webhook
 //   - POST /webhook/alertmanager — Alertmanager-formatted
+//
+// Agents whose trigger declares auth are only triggered by requests that
+// authenticate against it. A request that triggers no agent because it failed
+// authentication is rejected with 401. Triggers are written to the durable
+// queue; if that fails the request is rejected with 503 so the sender retries.
 func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -118,51 +221,230 @@ func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Look up agents for this source
 	h.mu.RLock()
-	agents, ok := h.agentMap[source]
+	regs := append([]webhookRegistration(nil), h.agentMap[source]...)
 	h.mu.RUnlock()
 
-	if !ok || len(agents) == 0 {
+	if len(regs) == 0 {
 		h.log.Info("No agents registered for webhook source", "source", source)
 		w.WriteHeader(http.StatusAccepted)
 		fmt.Fprintf(w, `{"status":"accepted","agents":0}`)
 		return
 	}
 
+	// Authenticate against each registration
+	var authorized []webhookRegistration
+	authFailure := ""
+	for _, reg := range regs {
+		if reason := h.authenticate(r.Context(), r, body, reg); reason != "" {
+			authFailure = reason
+			continue
+		}
+		authorized = append(authorized, reg)
+	}
+	if len(authorized) == 0 && authFailure != "" {
+		metrics.RecordWebhookAuthFailure(source, authFailure)
+		h.log.Info("Webhook rejected — authentication failed",
+			"source", source,
+			"reason", authFailure,
+			"remoteAddr", r.RemoteAddr,
+		)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Apply payload filters before debouncing, so filtered-out payloads
+	// don't consume the debounce window
+	var agents, alertAgents []types.NamespacedName
+	for _, reg := range authorized {
+		if reg.filter != nil {
+			matched, err := reg.filter.Match(r.Context(), body)
+			if err != nil {
+				h.log.V(1).Info("Webhook filter did not match",
+					"source", source,
+					"agent", reg.agentKey.String(),
+					"error", err.Error(),
+				)
+			}
+			if !matched {
+				continue
+			}
+		}
+		if reg.alertmanager {
+			alertAgents = append(alertAgents, reg.agentKey)
+			continue
+		}
+		agents = append(agents, reg.agentKey)
+	}
+
+	// Alertmanager triggers are deduped by groupKey instead of debounced
+	triggered, dropped := 0, 0
+	if len(alertAgents) > 0 {
+		t, d, err := h.dispatchAlert(r.Context(), source, body, alertAgents)
+		if err != nil {
+			h.log.Info("Invalid Alertmanager payload",
+				"source", source,
+				"error", err.Error(),
+			)
+			if len(agents) == 0 {
+				http.Error(w, "invalid alertmanager payload", http.StatusBadRequest)
+				return
+			}
+		}
+		triggered += t
+		dropped += d
+	}
+
 	// Trigger each matching agent (with debounce)
-	triggered := 0
 	for _, agentKey := range agents {
 		debounceKey := fmt.Sprintf("%s/%s/%s", source, agentKey.Namespace, agentKey.Name)
 
-		if h.debouncer.ShouldFire(debounceKey) {
-			select {
-			case h.triggers <- WebhookTrigger{
-				AgentKey: agentKey,
-				Source:   source,
-				Payload:  string(body),
-				Time:     time.Now(),
-			}:
-				triggered++
-			default:
-				h.log.Info("Webhook trigger channel full, dropping",
-					"agent", agentKey.String())
-			}
-		} else {
+		if !h.debouncer.ShouldFire(debounceKey) {
 			h.log.Info("Webhook debounced",
 				"source", source,
 				"agent", agentKey.String(),
 			)
+			continue
+		}
+
+		err := h.emit(r.Context(), WebhookTrigger{
+			AgentKey: agentKey,
+			Source:   source,
+			Payload:  string(body),
+			Time:     time.Now(),
+		})
+		if err != nil {
+			// Let the sender's retry through the debounce window
+			h.debouncer.Forget(debounceKey)
+			h.log.Error(err, "Failed to queue webhook trigger",
+				"source", source,
+				"agent", agentKey.String(),
+			)
+			dropped++
+			continue
 		}
+		triggered++
+	}
+
+	// Ask the sender to retry rather than silently losing the trigger
+	if dropped > 0 {
+		http.Error(w, "failed to queue trigger", http.StatusServiceUnavailable)
+		return
 	}
 
 	w.WriteHeader(http.StatusAccepted)
 	resp := map[string]interface{}{
 		"status":    "accepted",
-		"agents":    len(agents),
+		"agents":    len(agents) + len(alertAgents),
+		"filtered":  len(authorized) - len(agents) - len(alertAgents),
 		"triggered": triggered,
 	}
 	json.NewEncoder(w).Encode(resp)
 }
 
+// emit queues a webhook trigger, or hands it to the scheduler over the
+// in-memory channel when no queue is configured.
+func (h *WebhookHandler) emit(ctx context.Context, trigger WebhookTrigger) error {
+	if h.queue != nil {
+		return h.queue.Enqueue(ctx, trigger.AgentKey, corev1alpha1.RunTriggerWebhook, trigger.TriggerContext())
+	}
+	select {
+	case h.triggers <- trigger:
+		return nil
+	default:
+		return errTriggerChannelFull
+	}
+}
+
+// dispatchAlert parses an Alertmanager notification and emits an AlertTrigger
+// for each agent. Firing notifications are deduped per agent and groupKey and
+// queued as runs; resolved notifications are published on the event bus and
+// reset the group. It returns how many triggers were emitted and dropped.
+func (h *WebhookHandler) dispatchAlert(ctx context.Context, source string, body []byte, agents []types.NamespacedName) (int, int, error) {
+	payload, err := ParseAlertmanagerPayload(body)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	triggered, dropped := 0, 0
+	for _, agentKey := range agents {
+		groupKey := alertGroupKey(agentKey, payload.GroupKey)
+		if payload.Status == AlertStatusResolved {
+			h.groups.resolve(groupKey)
+		} else if !h.groups.observeFiring(groupKey, payload.firingAlerts()) {
+			h.log.Info("Alertmanager group already firing, deduped",
+				"source", source,
+				"agent", agentKey.String(),
+				"groupKey", payload.GroupKey,
+			)
+			continue
+		}
+
+		trigger := AlertTrigger{
+			AgentKey: agentKey,
+			Source:   source,
+			Alert:    payload,
+			Time:     time.Now(),
+		}
+		if err := h.emitAlert(ctx, trigger); err != nil {
+			// Forget the group so Alertmanager's retry isn't deduped
+			h.groups.resolve(groupKey)
+			h.log.Error(err, "Failed to queue alert trigger",
+				"source", source,
+				"agent", agentKey.String(),
+			)
+			dropped++
+			continue
+		}
+		triggered++
+	}
+	return triggered, dropped, nil
+}
+
+// emitAlert queues a firing alert as a run. Resolved alerts don't start a
+// run: they are published on the event bus before the request is answered,
+// so they survive whichever replica receives them.
+func (h *WebhookHandler) emitAlert(ctx context.Context, trigger AlertTrigger) error {
+	if trigger.Resolved() {
+		return h.publishResolved(ctx, trigger)
+	}
+	if h.queue != nil {
+		return h.queue.Enqueue(ctx, trigger.AgentKey, corev1alpha1.RunTriggerWebhook, trigger.TriggerContext())
+	}
+	select {
+	case h.alerts <- trigger:
+		return nil
+	default:
+		return errTriggerChannelFull
+	}
+}
+
+// publishResolved publishes an AgentEvent for a resolved Alertmanager group.
+func (h *WebhookHandler) publishResolved(ctx context.Context, trigger AlertTrigger) error {
+	if h.bus == nil {
+		h.log.Info("Alertmanager group resolved (no event bus configured)",
+			"agent", trigger.AgentKey.String(),
+			"groupKey", trigger.Alert.GroupKey,
+		)
+		return nil
+	}
+
+	tc := trigger.TriggerContext()
+	summary := "Alert group resolved"
+	if name := trigger.Alert.alertName(); name != "" {
+		summary = fmt.Sprintf("Alert %s resolved", name)
+	}
+	_, err := h.bus.Publish(ctx, events.PublishParams{
+		SourceAgent: trigger.AgentKey.Name,
+		Namespace:   trigger.AgentKey.Namespace,
+		EventType:   "alert.resolved",
+		Severity:    alertSeverity(trigger.Alert.CommonLabels),
+		Summary:     summary,
+		Detail:      tc.Payload,
+		Labels:      tc.Labels,
+	})
+	return err
+}
+
 // extractSource extracts the source name from a webhook URL path.
 // e.g. "/webhook/alertmanager" → "alertmanager"
 func extractSource(path string) string {
@@ -215,6 +497,13 @@ func (d *Debouncer) ShouldFire(key string) bool {
 	return true
 }
 
+// Forget clears the debounce state for a key, so the next event fires.
+func (d *Debouncer) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.last, key)
+}
+
 // Reset clears all debounce state.
 func (d *Debouncer) Reset() {
 	d.mu.Lock()
@@ -0,0 +1,138 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+// Webhook authentication failure reasons (the "reason" metric label).
+const (
+	authFailureMissing           = "missing-credentials"
+	authFailureInvalid           = "invalid-credentials"
+	authFailureSecretUnavailable = "secret-unavailable"
+)
+
+// errNoSecretReader means the handler was built without a client to read Secrets.
+var errNoSecretReader = errors.New("webhook handler has no secret reader")
+
+const (
+	defaultWebhookSecretKey = "secret"
+	defaultHMACHeader       = "X-Signature-256"
+	githubSignatureHeader   = "X-Hub-Signature-256"
+)
+
+// authenticate checks a request against a registration's auth spec.
+// It returns "" on success, or a failure reason.
+func (h *WebhookHandler) authenticate(ctx context.Context, r *http.Request, body []byte, reg webhookRegistration) string {
+	auth := reg.auth
+	if auth == nil {
+		return ""
+	}
+
+	// Reject requests without credentials before touching the Secret
+	presented := presentedCredential(r, auth)
+	if presented == "" {
+		return authFailureMissing
+	}
+
+	secret, err := h.webhookSecret(ctx, reg.agentKey.Namespace, auth)
+	if err != nil {
+		h.log.Error(err, "Failed to read webhook secret",
+			"agent", reg.agentKey.String(),
+			"secretRef", auth.SecretRef,
+		)
+		return authFailureSecretUnavailable
+	}
+
+	switch auth.Type {
+	case corev1alpha1.WebhookAuthBearer:
+		if subtle.ConstantTimeCompare([]byte(presented), secret) == 1 {
+			return ""
+		}
+	case corev1alpha1.WebhookAuthGitHub, corev1alpha1.WebhookAuthHMAC:
+		if validHMAC(presented, body, secret) {
+			return ""
+		}
+	}
+	return authFailureInvalid
+}
+
+// presentedCredential extracts the signature or token from the request.
+func presentedCredential(r *http.Request, auth *corev1alpha1.WebhookAuthSpec) string {
+	switch auth.Type {
+	case corev1alpha1.WebhookAuthGitHub:
+		return r.Header.Get(githubSignatureHeader)
+	case corev1alpha1.WebhookAuthHMAC:
+		header := auth.Header
+		if header == "" {
+			header = defaultHMACHeader
+		}
+		return r.Header.Get(header)
+	case corev1alpha1.WebhookAuthBearer:
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	default:
+		return ""
+	}
+}
+
+// validHMAC checks a hex HMAC-SHA256 signature of body, with or without
+// the "sha256=" prefix GitHub uses.
+func validHMAC(signature string, body, secret []byte) bool {
+	signature = strings.TrimPrefix(signature, "sha256=")
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// webhookSecret reads the shared secret or token from the referenced Secret
+// in the agent's namespace. Read per request so rotations apply immediately.
+func (h *WebhookHandler) webhookSecret(ctx context.Context, namespace string, auth *corev1alpha1.WebhookAuthSpec) ([]byte, error) {
+	if h.secrets == nil {
+		return nil, errNoSecretReader
+	}
+
+	secret := &corev1.Secret{}
+	if err := h.secrets.Get(ctx, types.NamespacedName{Namespace: namespace, Name: auth.SecretRef}, secret); err != nil {
+		return nil, err
+	}
+
+	key := auth.SecretKey
+	if key == "" {
+		key = defaultWebhookSecretKey
+	}
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("secret %s has no key %q", auth.SecretRef, key)
+	}
+	return value, nil
+}
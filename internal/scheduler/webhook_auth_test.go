@@ -0,0 +1,149 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package scheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+const testWebhookSecret = "s3cr3t-shared"
+
+func newAuthWebhookHandler(t *testing.T) *WebhookHandler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-auth", Namespace: "agents"},
+		Data:       map[string][]byte{"secret": []byte(testWebhookSecret)},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	return NewWebhookHandler(logf.Log.WithName("test"), time.Millisecond).WithSecrets(c)
+}
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(h *WebhookHandler, source, body string, headers map[string]string) int {
+	req := httptest.NewRequest("POST", "/webhook/"+source, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookAuth_GitHubSignature(t *testing.T) {
+	h := newAuthWebhookHandler(t)
+	h.RegisterAgentWithAuth("github", types.NamespacedName{Namespace: "agents", Name: "forge"},
+		&corev1alpha1.WebhookAuthSpec{Type: corev1alpha1.WebhookAuthGitHub, SecretRef: "webhook-auth"}, nil)
+
+	body := `{"action":"opened"}`
+	if code := postWebhook(h, "github", body, nil); code != http.StatusUnauthorized {
+		t.Errorf("missing signature: expected 401, got %d", code)
+	}
+	if code := postWebhook(h, "github", body, map[string]string{"X-Hub-Signature-256": "sha256=" + sign("tampered")}); code != http.StatusUnauthorized {
+		t.Errorf("wrong signature: expected 401, got %d", code)
+	}
+	if code := postWebhook(h, "github", body, map[string]string{"X-Hub-Signature-256": "sha256=" + sign(body)}); code != http.StatusAccepted {
+		t.Errorf("valid signature: expected 202, got %d", code)
+	}
+
+	select {
+	case <-h.Triggers():
+	case <-time.After(time.Second):
+		t.Error("timed out waiting for trigger")
+	}
+}
+
+func TestWebhookAuth_GenericHMACHeader(t *testing.T) {
+	h := newAuthWebhookHandler(t)
+	h.RegisterAgentWithAuth("ci", types.NamespacedName{Namespace: "agents", Name: "forge"},
+		&corev1alpha1.WebhookAuthSpec{Type: corev1alpha1.WebhookAuthHMAC, SecretRef: "webhook-auth", Header: "X-CI-Signature"}, nil)
+
+	body := `{"build":"failed"}`
+	if code := postWebhook(h, "ci", body, map[string]string{"X-Signature-256": sign(body)}); code != http.StatusUnauthorized {
+		t.Errorf("signature in wrong header: expected 401, got %d", code)
+	}
+	if code := postWebhook(h, "ci", body, map[string]string{"X-CI-Signature": sign(body)}); code != http.StatusAccepted {
+		t.Errorf("valid signature: expected 202, got %d", code)
+	}
+}
+
+func TestWebhookAuth_BearerToken(t *testing.T) {
+	h := newAuthWebhookHandler(t)
+	h.RegisterAgentWithAuth("alertmanager", types.NamespacedName{Namespace: "agents", Name: "watchman-light"},
+		&corev1alpha1.WebhookAuthSpec{Type: corev1alpha1.WebhookAuthBearer, SecretRef: "webhook-auth"}, nil)
+
+	if code := postWebhook(h, "alertmanager", "{}", map[string]string{"Authorization": "Bearer wrong"}); code != http.StatusUnauthorized {
+		t.Errorf("wrong token: expected 401, got %d", code)
+	}
+	if code := postWebhook(h, "alertmanager", "{}", map[string]string{"Authorization": "Bearer " + testWebhookSecret}); code != http.StatusAccepted {
+		t.Errorf("valid token: expected 202, got %d", code)
+	}
+}
+
+func TestWebhookAuth_MissingSecretRejects(t *testing.T) {
+	h := newAuthWebhookHandler(t)
+	h.RegisterAgentWithAuth("alertmanager", types.NamespacedName{Namespace: "agents", Name: "watchman-light"},
+		&corev1alpha1.WebhookAuthSpec{Type: corev1alpha1.WebhookAuthBearer, SecretRef: "does-not-exist"}, nil)
+
+	if code := postWebhook(h, "alertmanager", "{}", map[string]string{"Authorization": "Bearer anything"}); code != http.StatusUnauthorized {
+		t.Errorf("unreadable secret must fail closed: expected 401, got %d", code)
+	}
+}
+
+func TestWebhookAuth_UnauthenticatedRegistrationStillTriggers(t *testing.T) {
+	h := newAuthWebhookHandler(t)
+	open := types.NamespacedName{Namespace: "agents", Name: "watchman-light"}
+	h.RegisterAgent("alertmanager", open)
+	h.RegisterAgentWithAuth("alertmanager", types.NamespacedName{Namespace: "agents", Name: "forge"},
+		&corev1alpha1.WebhookAuthSpec{Type: corev1alpha1.WebhookAuthBearer, SecretRef: "webhook-auth"}, nil)
+
+	if code := postWebhook(h, "alertmanager", "{}", nil); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+
+	select {
+	case trigger := <-h.Triggers():
+		if trigger.AgentKey != open {
+			t.Errorf("expected only %v to trigger, got %v", open, trigger.AgentKey)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for trigger")
+	}
+	select {
+	case trigger := <-h.Triggers():
+		t.Errorf("authenticated agent %v triggered without credentials", trigger.AgentKey)
+	default:
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// minSecretLength is the shortest known secret value a Redactor replaces
+// verbatim; shorter values would redact ordinary words.
+const minSecretLength = 8
+
+// Redactor scrubs text bound for an LLM provider. It applies the built-in
+// sensitive patterns, any extra patterns (e.g. from the agent's
+// environment), and replaces known secret values wherever they appear,
+// counting each value it redacts.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	secrets  []string
+}
+
+// NewRedactor builds a redactor. Extra patterns use RE2 syntax; as with the
+// built-in patterns, a first capture group is kept as a prefix. Secrets are
+// literal values, such as resolved credentials; those shorter than eight
+// characters are ignored.
+func NewRedactor(extraPatterns []string, secrets []string) (*Redactor, error) {
+	r := &Redactor{patterns: append([]*regexp.Regexp{}, sensitivePatterns...)}
+	for _, p := range extraPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range secrets {
+		if len(s) >= minSecretLength && !seen[s] {
+			seen[s] = true
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	// Longest first, so a secret containing another is replaced whole
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	return r, nil
+}
+
+// Redact returns the text with secrets replaced by [REDACTED], and how many
+// were replaced. A nil Redactor applies only the built-in patterns.
+func (r *Redactor) Redact(text string) (string, int) {
+	if text == "" {
+		return text, 0
+	}
+	patterns := sensitivePatterns
+	var secrets []string
+	if r != nil {
+		patterns, secrets = r.patterns, r.secrets
+	}
+
+	total := 0
+	for _, s := range secrets {
+		if n := strings.Count(text, s); n > 0 {
+			text = strings.ReplaceAll(text, s, redactedPlaceholder)
+			total += n
+		}
+	}
+	for _, pattern := range patterns {
+		var n int
+		text, n = redactPattern(pattern, text)
+		total += n
+	}
+	return text, total
+}
+
+// CredentialValues returns the values of resolved credentials that should
+// never reach an LLM provider, skipping fields that name rather than
+// authenticate (user, host, port).
+func CredentialValues(creds map[string]map[string]string) []string {
+	var values []string
+	for _, data := range creds {
+		for k, v := range data {
+			switch strings.ToLower(k) {
+			case "user", "username", "host", "hostname", "port":
+				continue
+			}
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package skill
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Precondition fail actions.
+const (
+	// FailActionBlock blocks the action (the default).
+	FailActionBlock = "block"
+
+	// FailActionEscalate sends the action for human approval.
+	FailActionEscalate = "escalate"
+
+	// FailActionSkip skips the action without treating it as a violation.
+	FailActionSkip = "skip"
+)
+
+// Check operators.
+const (
+	CheckContains    = "contains"
+	CheckNotContains = "!contains"
+	CheckMatches     = "matches"
+	CheckNotMatches  = "!matches"
+)
+
+// Check is a parsed precondition. Its source form is a read-only tool call
+// followed by an optional assertion on the tool's output:
+//
+//	kubectl.get resource=pod name=${name} namespace=${namespace} contains "CrashLoopBackOff"
+//	action:pod-events name=${name} matches "Back-off restarting .* \(x[0-9]{2,}"
+//	http.get url=https://${host}/healthz !contains "ok"
+//
+// The call is either a tool name or "action:<id>", naming a declared read
+// action whose tool is called. Arguments are key=value pairs; values may be
+// double-quoted and may reference the checked action's arguments as
+// ${name}. The assertion is one of contains, !contains, matches or
+// !matches (a Go regular expression) followed by a value. ${name} values
+// in a pattern match literally. Without an assertion, the check passes if
+// the call succeeds.
+type Check struct {
+	// Tool is the tool to call, if the check names one.
+	Tool string
+
+	// Action is the ID of the read action to call, if the check names one.
+	Action string
+
+	// Args are the call arguments, before ${name} expansion.
+	Args map[string]string
+
+	// Op is the assertion operator, or "" for none.
+	Op string
+
+	// Value is the assertion operand.
+	Value string
+}
+
+// ParseCheck parses a precondition check.
+func ParseCheck(check string) (*Check, error) {
+	fields, err := splitFields(check)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty check")
+	}
+
+	c := &Check{Args: make(map[string]string)}
+	if id, ok := strings.CutPrefix(fields[0], "action:"); ok {
+		if id == "" {
+			return nil, fmt.Errorf("missing action ID after \"action:\"")
+		}
+		c.Action = id
+	} else {
+		c.Tool = fields[0]
+	}
+
+	rest := fields[1:]
+	for len(rest) > 0 {
+		switch op := rest[0]; op {
+		case CheckContains, CheckNotContains, CheckMatches, CheckNotMatches:
+			if len(rest) != 2 {
+				return nil, fmt.Errorf("%s takes exactly one value", op)
+			}
+			c.Op, c.Value = op, rest[1]
+			if op == CheckMatches || op == CheckNotMatches {
+				if _, err := regexp.Compile(c.Value); err != nil {
+					return nil, fmt.Errorf("invalid pattern %q: %w", c.Value, err)
+				}
+			}
+			return c, nil
+		}
+		key, value, ok := strings.Cut(rest[0], "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("argument %q is not key=value", rest[0])
+		}
+		c.Args[key] = value
+		rest = rest[1:]
+	}
+	return c, nil
+}
+
+// Assert reports whether output satisfies the check's assertion, after
+// expanding ${name} references in its value from vars. If it doesn't, the
+// returned string says why.
+func (c *Check) Assert(output string, vars map[string]interface{}) (bool, string) {
+	value := Expand(c.Value, vars)
+	switch c.Op {
+	case CheckContains:
+		if !strings.Contains(output, value) {
+			return false, fmt.Sprintf("output does not contain %q", value)
+		}
+	case CheckNotContains:
+		if strings.Contains(output, value) {
+			return false, fmt.Sprintf("output contains %q", value)
+		}
+	case CheckMatches, CheckNotMatches:
+		// Arguments come from the model, so they can't be allowed to
+		// change the pattern
+		value = Expand(c.Value, quoteVars(vars))
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return false, fmt.Sprintf("invalid pattern %q: %v", value, err)
+		}
+		if matched := re.MatchString(output); matched != (c.Op == CheckMatches) {
+			if matched {
+				return false, fmt.Sprintf("output matches %q", value)
+			}
+			return false, fmt.Sprintf("output does not match %q", value)
+		}
+	}
+	return true, ""
+}
+
+// quoteVars returns vars with each value quoted for use in a regular
+// expression.
+func quoteVars(vars map[string]interface{}) map[string]interface{} {
+	quoted := make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		if v != nil {
+			quoted[k] = regexp.QuoteMeta(fmt.Sprint(v))
+		}
+	}
+	return quoted
+}
+
+// splitFields splits s on whitespace, keeping double-quoted strings (which
+// may follow a key=) together. Inside quotes, \" and \\ are escapes and any
+// other backslash is kept, so regular expressions need no double escaping.
+func splitFields(s string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted := false, false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quoted && ch == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\'):
+			i++
+			field.WriteByte(s[i])
+		case ch == '"':
+			quoted = !quoted
+			inField = true
+		case !quoted && (ch == ' ' || ch == '\t' || ch == '\n'):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteByte(ch)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package skill
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCheck(t *testing.T) {
+	c, err := ParseCheck(`kubectl.get resource=pod name=${name} labelSelector="app=api, tier=web" matches "Back-off .* \(x[0-9]+"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Tool != "kubectl.get" || c.Op != CheckMatches || c.Value != `Back-off .* \(x[0-9]+` {
+		t.Errorf("unexpected check %+v", c)
+	}
+	if c.Args["name"] != "${name}" || c.Args["labelSelector"] != "app=api, tier=web" {
+		t.Errorf("unexpected args %#v", c.Args)
+	}
+
+	c, err = ParseCheck("action:get-pods namespace=prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Action != "get-pods" || c.Tool != "" || c.Op != "" {
+		t.Errorf("unexpected check %+v", c)
+	}
+}
+
+func TestParseCheck_Errors(t *testing.T) {
+	tests := map[string]string{
+		"":                                "empty check",
+		"action: name=x":                  "missing action ID",
+		"kubectl.get pods":                "not key=value",
+		"kubectl.get contains":            "exactly one value",
+		`kubectl.get contains "a" "b"`:    "exactly one value",
+		`kubectl.get matches "(unclosed"`: "invalid pattern",
+		`kubectl.get contains "unclosed`:  "unterminated quote",
+	}
+	for check, want := range tests {
+		if _, err := ParseCheck(check); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseCheck(%q) = %v, want error containing %q", check, err, want)
+		}
+	}
+}
+
+func TestCheckAssert(t *testing.T) {
+	vars := map[string]interface{}{"name": "api-0", "pattern": ".*"}
+	tests := []struct {
+		op, value, output string
+		want              bool
+	}{
+		{CheckContains, "${name}", "api-0 CrashLoopBackOff", true},
+		{CheckContains, "Running", "api-0 CrashLoopBackOff", false},
+		{CheckNotContains, "Running", "api-0 CrashLoopBackOff", true},
+		{CheckNotContains, "Crash", "api-0 CrashLoopBackOff", false},
+		{CheckMatches, `restarts: [1-9][0-9]+`, "restarts: 14", true},
+		{CheckMatches, `restarts: [1-9][0-9]+`, "restarts: 3", false},
+		{CheckNotMatches, `restarts: [1-9][0-9]+`, "restarts: 3", true},
+		{CheckMatches, `^pod/${name} `, "pod/api-0 Running", true},
+		{CheckMatches, `^pod/${pattern}$`, "pod/api-0", false},
+		{CheckNotMatches, `^pod/${pattern}$`, "pod/api-0", true},
+		{CheckMatches, `^pod/${pattern}$`, "pod/.*", true},
+		{"", "", "anything", true},
+	}
+	for _, tt := range tests {
+		c := &Check{Op: tt.op, Value: tt.value}
+		if got, why := c.Assert(tt.output, vars); got != tt.want {
+			t.Errorf("%s %q on %q = %v (%s), want %v", tt.op, tt.value, tt.output, got, why, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package skill
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseCommand parses a rollback command such as
+//
+//	kubectl.scale resource=deployment name=${name} namespace=${namespace} replicas=2
+//
+// into a tool name and tool arguments. Values may be double-quoted, as in
+// a Check. ${name} references are replaced with the matching value from
+// vars (the arguments of the action being undone); unknown references are
+// left unchanged. Values that read as booleans or numbers
+// are passed as such, the way a model would send them.
+func ParseCommand(command string, vars map[string]interface{}) (string, map[string]interface{}, error) {
+	fields, err := splitFields(command)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("empty command")
+	}
+	args := make(map[string]interface{}, len(fields)-1)
+	for _, f := range fields[1:] {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return "", nil, fmt.Errorf("argument %q is not key=value", f)
+		}
+		args[key] = argValue(Expand(value, vars))
+	}
+	return fields[0], args, nil
+}
+
+// ExpandArgs expands ${name} references in each argument value, as
+// ParseCommand does.
+func ExpandArgs(args map[string]string, vars map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		out[k] = argValue(Expand(v, vars))
+	}
+	return out
+}
+
+// referencePattern matches a ${name} reference.
+var referencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Expand replaces ${name} references in s with values from vars. Anything
+// else is left as it is, including a bare $name, a $ in a pattern, and
+// references to names vars doesn't have.
+func Expand(s string, vars map[string]interface{}) string {
+	return referencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		v, ok := vars[ref[2:len(ref)-1]]
+		switch {
+		case !ok:
+			return ref
+		case v == nil:
+			return ""
+		default:
+			return fmt.Sprint(v)
+		}
+	})
+}
+
+func argValue(s string) interface{} {
+	if b, err := strconv.ParseBool(s); err == nil && (s == "true" || s == "false") {
+		return b
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package skill
+
+import (
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	vars := map[string]interface{}{"name": "api", "namespace": "prod", "replicas": float64(5)}
+	tool, args, err := ParseCommand("kubectl.scale resource=deployment name=${name} namespace=${namespace} replicas=2 force=true", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tool != "kubectl.scale" {
+		t.Errorf("tool = %q", tool)
+	}
+	want := map[string]interface{}{"resource": "deployment", "name": "api", "namespace": "prod", "replicas": float64(2), "force": true}
+	for k, v := range want {
+		if args[k] != v {
+			t.Errorf("args[%s] = %#v, want %#v", k, args[k], v)
+		}
+	}
+
+	if _, _, err := ParseCommand("kubectl.scale replicas", nil); err == nil {
+		t.Error("expected an error for an argument without a value")
+	}
+	if _, _, err := ParseCommand("  ", nil); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+}
+
+func TestExpandArgs(t *testing.T) {
+	args := ExpandArgs(map[string]string{"name": "${name}", "tailLines": "${lines}", "missing": "${nope}"},
+		map[string]interface{}{"name": "api", "lines": float64(20)})
+	if args["name"] != "api" || args["tailLines"] != float64(20) || args["missing"] != "${nope}" {
+		t.Errorf("unexpected expansion: %#v", args)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]interface{}{"name": "api", "replicas": float64(3), "unset": nil}
+	tests := []struct {
+		in, want string
+	}{
+		{"${name}-svc", "api-svc"},
+		{"${replicas}/${replicas}", "3/3"},
+		{"${unset}", ""},
+		// Only ${name} is a reference
+		{"$name", "$name"},
+		{"ready$", "ready$"},
+		{"^api-[a-z0-9]+$", "^api-[a-z0-9]+$"},
+		{"$$HOME", "$$HOME"},
+		// Unknown names are left unchanged
+		{"${nope}", "${nope}"},
+		{"${name} ${nope}", "api ${nope}"},
+		{"${not a name}", "${not a name}"},
+	}
+	for _, tt := range tests {
+		if got := Expand(tt.in, vars); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
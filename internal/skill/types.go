@@ -69,9 +69,18 @@ type Action struct {
 	// PreConditions are checks that must pass before execution.
 	PreConditions []PreCondition `yaml:"preConditions"`
 
+	// PostCheck verifies the action after it executes. If it fails, the
+	// action's rollback is run.
+	PostCheck *PostCheck `yaml:"postCheck,omitempty"`
+
 	// Rollback describes how to undo this action.
 	Rollback *RollbackSpec `yaml:"rollback,omitempty"`
 
+	// Idempotent declares that repeating the action has the same effect as
+	// running it once, so a mutation may be retried after a transient
+	// failure. Reads are always retried.
+	Idempotent bool `yaml:"idempotent,omitempty"`
+
 	// Cooldown is the minimum time between executions (e.g. "300s").
 	Cooldown string `yaml:"cooldown,omitempty"`
 
@@ -84,13 +93,42 @@ type Action struct {
 
 // PreCondition is a check that runs before an action.
 type PreCondition struct {
-	Check      string `yaml:"check"`
+	// Check is a read-only tool call with an optional assertion on its
+	// output (see ParseCheck).
+	Check string `yaml:"check"`
+
+	// FailAction is what happens when the check fails: block (the
+	// default), escalate or skip.
 	FailAction string `yaml:"failAction"`
 }
 
+// PostCheck is a read-only tool call that verifies an action worked.
+// Argument values may reference the action's own arguments as ${name}.
+type PostCheck struct {
+	// Tool is the read-tier tool to call (e.g. "kubectl.get").
+	Tool string `yaml:"tool"`
+
+	// Args are the tool arguments.
+	Args map[string]string `yaml:"args,omitempty"`
+
+	// Expect is text the tool output must contain. If empty, the check
+	// passes whenever the tool succeeds.
+	Expect string `yaml:"expect,omitempty"`
+
+	// Delay is how long to wait before checking (e.g. "30s").
+	Delay string `yaml:"delay,omitempty"`
+}
+
 // RollbackSpec describes how to undo an action.
 type RollbackSpec struct {
 	Description string `yaml:"description"`
-	Automatic   bool   `yaml:"automatic"`
-	Command     string `yaml:"command,omitempty"`
+
+	// Automatic runs the rollback without asking. Otherwise it is offered
+	// through an ApprovalRequest.
+	Automatic bool `yaml:"automatic"`
+
+	// Command is the tool call that undoes the action: a tool name followed
+	// by key=value arguments (see ParseCommand). Without a command the
+	// rollback is documentation only.
+	Command string `yaml:"command,omitempty"`
 }
@@ -13,6 +13,7 @@ package skill
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // ValidationResult holds the outcome of skill validation.
@@ -92,6 +93,46 @@ func Validate(skill *Skill) *ValidationResult {
 				result.Warnings = append(result.Warnings,
 					fmt.Sprintf("%s (%s): missing description", prefix, action.ID))
 			}
+
+			for j, pc := range action.PreConditions {
+				pcPrefix := fmt.Sprintf("%s: preConditions[%d]", prefix, j)
+				check, err := ParseCheck(pc.Check)
+				if err != nil {
+					result.Valid = false
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: invalid check: %v", pcPrefix, err))
+				} else if check.Action != "" && !declaresAction(skill.Actions, check.Action) {
+					result.Warnings = append(result.Warnings,
+						fmt.Sprintf("%s: action %q is not declared in this skill", pcPrefix, check.Action))
+				}
+				switch pc.FailAction {
+				case "", FailActionBlock, FailActionEscalate, FailActionSkip:
+				default:
+					result.Valid = false
+					result.Errors = append(result.Errors,
+						fmt.Sprintf("%s: invalid failAction %q (must be block|escalate|skip)", pcPrefix, pc.FailAction))
+				}
+			}
+
+			if action.PostCheck != nil {
+				if action.PostCheck.Tool == "" {
+					result.Valid = false
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: postCheck: missing required field: tool", prefix))
+				}
+				if action.PostCheck.Delay != "" {
+					if _, err := time.ParseDuration(action.PostCheck.Delay); err != nil {
+						result.Valid = false
+						result.Errors = append(result.Errors,
+							fmt.Sprintf("%s: postCheck: invalid delay %q", prefix, action.PostCheck.Delay))
+					}
+				}
+			}
+
+			if action.Rollback != nil && action.Rollback.Command != "" {
+				if _, _, err := ParseCommand(action.Rollback.Command, nil); err != nil {
+					result.Valid = false
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: rollback command: %v", prefix, err))
+				}
+			}
 		}
 
 		// Check for duplicate action IDs
@@ -128,3 +169,13 @@ func MustValidate(skill *Skill) error {
 	}
 	return nil
 }
+
+// declaresAction reports whether the sheet declares an action with the given ID.
+func declaresAction(sheet *ActionSheet, id string) bool {
+	for _, a := range sheet.Actions {
+		if a.ID == id {
+			return true
+		}
+	}
+	return false
+}
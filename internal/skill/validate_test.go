@@ -11,6 +11,7 @@ You may obtain a copy of the License at
 package skill
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -237,3 +238,52 @@ func TestMustValidate_Invalid(t *testing.T) {
 		t.Error("expected error for invalid skill")
 	}
 }
+
+func TestValidate_RollbackAndPostCheck(t *testing.T) {
+	skill := &Skill{
+		Name:         "test",
+		Description:  "test",
+		Instructions: "do stuff",
+		Actions: &ActionSheet{
+			Actions: []Action{
+				{ID: "scale", Tool: "kubectl.scale", Tier: "service-mutation",
+					PostCheck: &PostCheck{Delay: "soon"},
+					Rollback:  &RollbackSpec{Command: "kubectl.scale replicas"}},
+			},
+		},
+	}
+
+	result := Validate(skill)
+	if result.Valid || len(result.Errors) != 3 {
+		t.Errorf("expected 3 errors (postCheck tool, delay, rollback command), got %v", result.Errors)
+	}
+}
+
+func TestValidate_PreConditions(t *testing.T) {
+	skill := &Skill{
+		Name:         "test",
+		Description:  "test",
+		Instructions: "do stuff",
+		Actions: &ActionSheet{
+			Actions: []Action{
+				{ID: "restart", Tool: "kubectl.rollout", Tier: "service-mutation",
+					PreConditions: []PreCondition{
+						{Check: `action:pod-status name=${name} contains "CrashLoopBackOff"`, FailAction: "skip"},
+						{Check: "deployment has >0 ready replicas", FailAction: "abort"},
+					}},
+			},
+		},
+	}
+
+	result := Validate(skill)
+	if result.Valid || len(result.Errors) != 2 {
+		t.Errorf("expected 2 errors (check syntax, failAction), got %v", result.Errors)
+	}
+	found := false
+	for _, w := range result.Warnings {
+		found = found || strings.Contains(w, `"pod-status" is not declared`)
+	}
+	if !found {
+		t.Errorf("expected a warning for the undeclared action, got %v", result.Warnings)
+	}
+}
@@ -114,6 +114,12 @@ func StartLLMCallSpan(ctx context.Context, model, provider string, iteration int
 	)
 }
 
+// SetLLMFallback marks an LLM span as a call to a fallback model, by its
+// position in the tier's fallback chain (1 for the first fallback).
+func SetLLMFallback(span trace.Span, position int) {
+	span.SetAttributes(attribute.Int("legator.model_fallback", position))
+}
+
 // EndLLMCallSpan enriches the LLM span with usage data.
 func EndLLMCallSpan(span trace.Span, inputTokens, outputTokens int64, hasToolCalls bool) {
 	span.SetAttributes(
@@ -121,6 +121,28 @@ func TestStartLLMCallSpan(t *testing.T) {
 	}
 }
 
+func TestSetLLMFallback(t *testing.T) {
+	exporter := setupTestTracer(t)
+
+	_, llmSpan := StartLLMCallSpan(context.Background(), "gpt-4o", "openai", 2)
+	SetLLMFallback(llmSpan, 1)
+	EndLLMCallSpan(llmSpan, 100, 50, false)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	found := false
+	for _, a := range spans[0].Attributes {
+		if string(a.Key) == "legator.model_fallback" && a.Value.AsInt64() == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("missing legator.model_fallback")
+	}
+}
+
 func TestStartToolCallSpan(t *testing.T) {
 	exporter := setupTestTracer(t)
 
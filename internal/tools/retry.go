@@ -0,0 +1,140 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RetryableTool extends Tool with failure classification. The runner only
+// retries calls to tools that implement it, and only when Retryable says
+// the failure is transient.
+type RetryableTool interface {
+	Tool
+
+	// Retryable reports whether a call that returned output and err might
+	// succeed if repeated unchanged.
+	Retryable(output string, err error) bool
+}
+
+// transientMessages are error texts of transient failures that reach tools
+// without a wrapped cause (e.g. formatted with %v).
+var transientMessages = []string{
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"i/o timeout",
+	"timed out",
+	"no route to host",
+	"network is unreachable",
+	"tls handshake timeout",
+	"unexpected eof",
+}
+
+// IsTransientError reports whether err looks like a failure that may not
+// recur: timeouts, refused or reset connections, and API server overload
+// or internal errors. Cancellation of the caller's context is not transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	case apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsTooManyRequests(err),
+		apierrors.IsInternalError(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsUnexpectedServerError(err):
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpServerError reports whether an HTTP tool's output (see
+// formatHTTPResponse) carries a 5xx status.
+func httpServerError(output string) bool {
+	rest, ok := strings.CutPrefix(output, "HTTP ")
+	if !ok || len(rest) < 3 {
+		return false
+	}
+	code, err := strconv.Atoi(rest[:3])
+	return err == nil && code >= 500 && code <= 599
+}
+
+// Retryable implements RetryableTool.
+func (t *HTTPGetTool) Retryable(output string, err error) bool {
+	return IsTransientError(err) || (err == nil && httpServerError(output))
+}
+
+// Retryable implements RetryableTool.
+func (t *HTTPPostTool) Retryable(output string, err error) bool {
+	return IsTransientError(err) || (err == nil && httpServerError(output))
+}
+
+// Retryable implements RetryableTool.
+func (t *HTTPDeleteTool) Retryable(output string, err error) bool {
+	return IsTransientError(err) || (err == nil && httpServerError(output))
+}
+
+// Retryable implements RetryableTool. Dial failures, stale sessions and
+// timeouts are transient; a command's own non-zero exit and configuration
+// errors (unknown host, root login refused) are not.
+func (t *SSHTool) Retryable(_ string, err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(err.Error(), "ssh: session creation failed") || IsTransientError(err)
+}
+
+// Retryable implements RetryableTool.
+func (t *KubectlGetTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
+
+// Retryable implements RetryableTool.
+func (t *KubectlDescribeTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
+
+// Retryable implements RetryableTool.
+func (t *KubectlLogsTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
+
+// Retryable implements RetryableTool.
+func (t *KubectlApplyTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
+
+// Retryable implements RetryableTool.
+func (t *KubectlRolloutTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
+
+// Retryable implements RetryableTool.
+func (t *KubectlScaleTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
+
+// Retryable implements RetryableTool.
+func (t *KubectlDeleteTool) Retryable(_ string, err error) bool { return IsTransientError(err) }
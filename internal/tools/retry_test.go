@@ -0,0 +1,75 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsTransientError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"deadline", fmt.Errorf("get: %w", context.DeadlineExceeded), true},
+		{"canceled", fmt.Errorf("get: %w", context.Canceled), false},
+		{"reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"refused text", errors.New("ssh: connection failed to web-1 — dial tcp 10.0.0.1:22: connect: connection refused"), true},
+		{"api overloaded", apierrors.NewTooManyRequests("slow down", 1), true},
+		{"api unavailable", apierrors.NewServiceUnavailable("etcd"), true},
+		{"api internal", apierrors.NewInternalError(errors.New("boom")), true},
+		{"not found", apierrors.NewNotFound(gr, "api"), false},
+		{"forbidden", apierrors.NewForbidden(gr, "api", errors.New("rbac")), false},
+		{"validation", errors.New("url is required"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransientError(tt.err); got != tt.want {
+			t.Errorf("%s: IsTransientError(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPRetryable(t *testing.T) {
+	tool := &HTTPGetTool{}
+	if !tool.Retryable("HTTP 503 503 Service Unavailable\n\n", nil) {
+		t.Error("5xx responses should be retryable")
+	}
+	if tool.Retryable("HTTP 404 404 Not Found\n\n", nil) {
+		t.Error("4xx responses should not be retryable")
+	}
+	if tool.Retryable("HTTP 200 200 OK\n\nHTTP 500 in the body", nil) {
+		t.Error("only the status line counts")
+	}
+}
+
+func TestSSHRetryable(t *testing.T) {
+	tool := &SSHTool{}
+	if tool.Retryable("", errors.New(`ssh: connection failed to web-1 — no SSH credential configured for host "web-1"`)) {
+		t.Error("configuration errors should not be retryable")
+	}
+	if !tool.Retryable("", errors.New("ssh: session creation failed — EOF")) {
+		t.Error("session failures should be retryable")
+	}
+	if tool.Retryable("exit 1", nil) {
+		t.Error("a command's own failure should not be retryable")
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/security"
+	"github.com/marcus-qen/legator/internal/tools"
+)
+
+// ErrPlaybackExhausted is returned once every recorded turn has been played.
+var ErrPlaybackExhausted = errors.New("transcript playback exhausted")
+
+// PlaybackProvider is a provider.Provider that answers each Complete call
+// with the next assistant turn from a transcript, recording the requests it
+// receives.
+type PlaybackProvider struct {
+	mu       sync.Mutex
+	turns    []Message
+	next     int
+	requests []*provider.CompletionRequest
+}
+
+// NewPlaybackProvider plays back the assistant turns of t.
+func NewPlaybackProvider(t *Transcript) *PlaybackProvider {
+	p := &PlaybackProvider{}
+	for _, m := range t.Messages {
+		if m.Role == "assistant" {
+			p.turns = append(p.turns, m)
+		}
+	}
+	return p
+}
+
+// Complete returns the next recorded assistant turn.
+func (p *PlaybackProvider) Complete(_ context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, req)
+	if p.next >= len(p.turns) {
+		return nil, ErrPlaybackExhausted
+	}
+	turn := p.turns[p.next]
+	p.next++
+
+	resp := &provider.CompletionResponse{Content: turn.Content, StopReason: "end_turn"}
+	for _, tc := range turn.ToolCalls {
+		resp.ToolCalls = append(resp.ToolCalls, provider.ToolCall{ID: tc.ID, Name: tc.Name, Args: tc.Args})
+	}
+	if len(resp.ToolCalls) > 0 {
+		resp.StopReason = "tool_use"
+	}
+	return resp, nil
+}
+
+// Name returns the provider identifier.
+func (p *PlaybackProvider) Name() string { return "playback" }
+
+// Requests returns the requests received so far.
+func (p *PlaybackProvider) Requests() []*provider.CompletionRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*provider.CompletionRequest(nil), p.requests...)
+}
+
+// RecordedTools returns a registry of stand-ins for the tools a transcript
+// called. Each answers a call with the output recorded for a call with the
+// same arguments — in order, repeating the last once they run out — and
+// fails for arguments that were never called, such as a precondition check
+// whose read the model didn't make. Nothing is executed.
+func RecordedTools(t *Transcript) *tools.Registry {
+	outputs := make(map[string][]ToolResult)
+	results := make(map[string]ToolResult)
+	for _, m := range t.Messages {
+		for _, tr := range m.ToolResults {
+			results[tr.ToolCallID] = tr
+		}
+	}
+
+	reg := tools.NewRegistry()
+	for _, m := range t.Messages {
+		for _, tc := range m.ToolCalls {
+			if _, found := reg.Get(tc.Name); !found {
+				reg.Register(&recordedTool{name: tc.Name, outputs: outputs})
+			}
+			res, ok := results[tc.ID]
+			if !ok {
+				continue
+			}
+			// Only the output of calls that ran is tool output
+			if output, ok := security.UnscreenToolOutput(res.Content); ok {
+				res.Content = output
+				key := callKey(tc.Name, tc.Args)
+				outputs[key] = append(outputs[key], res)
+			}
+		}
+	}
+	return reg
+}
+
+// recordedTool plays back a tool's recorded outputs.
+type recordedTool struct {
+	name    string
+	outputs map[string][]ToolResult
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (t *recordedTool) Name() string                       { return t.name }
+func (t *recordedTool) Description() string                { return t.name }
+func (t *recordedTool) Parameters() map[string]interface{} { return nil }
+
+func (t *recordedTool) Execute(_ context.Context, args map[string]interface{}) (string, error) {
+	key := callKey(t.name, args)
+	outputs := t.outputs[key]
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("%s: no output was recorded for these arguments", t.name)
+	}
+
+	t.mu.Lock()
+	if t.calls == nil {
+		t.calls = make(map[string]int)
+	}
+	res := outputs[min(t.calls[key], len(outputs)-1)]
+	t.calls[key]++
+	t.mu.Unlock()
+
+	if res.IsError {
+		return "", errors.New(res.Content)
+	}
+	return res.Content, nil
+}
+
+// callKey identifies a tool call by its name and arguments.
+func callKey(name string, args map[string]interface{}) string {
+	b, _ := json.Marshal(args)
+	return name + " " + string(b)
+}
+
+// Outcome classifies what happened to a tool call.
+type Outcome string
+
+const (
+	// OutcomeAllowed means the call ran (successfully or not).
+	OutcomeAllowed Outcome = "allowed"
+
+	// OutcomeApproval means the call needed human approval.
+	OutcomeApproval Outcome = "approval"
+
+	// OutcomeBlocked means guardrails blocked the call.
+	OutcomeBlocked Outcome = "blocked"
+
+	// OutcomeSkipped means a precondition found the call unnecessary.
+	OutcomeSkipped Outcome = "skipped"
+
+	// OutcomeNotReached means the replayed run ended before the call.
+	OutcomeNotReached Outcome = "not-reached"
+)
+
+// Step is one recorded action compared with its replay.
+type Step struct {
+	Seq    int32
+	Tool   string
+	Target string
+
+	// Recorded is what happened in the original run.
+	Recorded       Outcome
+	RecordedStatus corev1alpha1.ActionStatus
+
+	// Replayed is what happens now. Reason says why, if the call didn't run.
+	Replayed       Outcome
+	ReplayedStatus corev1alpha1.ActionStatus
+	Reason         string
+}
+
+// Changed reports whether the replay ends differently.
+func (s Step) Changed() bool {
+	return s.Recorded != s.Replayed
+}
+
+// Compare pairs each action recorded in a transcript with the same tool
+// call in a replay of it, in the original order.
+func Compare(recorded, replayed *Transcript) []Step {
+	calls := make(map[string]ToolCall)
+	for _, m := range replayed.Messages {
+		for _, tc := range m.ToolCalls {
+			calls[tc.ID] = tc
+		}
+	}
+
+	var steps []Step
+	for _, m := range recorded.Messages {
+		for _, tc := range m.ToolCalls {
+			if tc.Status == "" {
+				// Not an action (e.g. report.finding)
+				continue
+			}
+			step := Step{
+				Seq:            tc.Seq,
+				Tool:           tc.Name,
+				Target:         tools.ExtractTarget(tc.Name, tc.Args),
+				Recorded:       statusOutcome(tc.Status),
+				RecordedStatus: tc.Status,
+				Replayed:       OutcomeNotReached,
+			}
+			if now, ok := calls[tc.ID]; ok && now.Status != "" {
+				step.Replayed = statusOutcome(now.Status)
+				step.ReplayedStatus = now.Status
+				step.Reason = now.Reason
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+func statusOutcome(status corev1alpha1.ActionStatus) Outcome {
+	switch status {
+	case corev1alpha1.ActionStatusExecuted, corev1alpha1.ActionStatusFailed, corev1alpha1.ActionStatusPlanned:
+		return OutcomeAllowed
+	case corev1alpha1.ActionStatusApproved, corev1alpha1.ActionStatusDenied, corev1alpha1.ActionStatusPendingApproval:
+		return OutcomeApproval
+	case corev1alpha1.ActionStatusSkipped:
+		return OutcomeSkipped
+	default:
+		return OutcomeBlocked
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package transcript
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/security"
+)
+
+func replayTestTranscript() *Transcript {
+	args := func(name string) map[string]interface{} {
+		return map[string]interface{}{"resource": "deployment", "name": name}
+	}
+	return &Transcript{
+		Version: Version,
+		Agent:   "watchman",
+		Messages: []Message{
+			{Role: "user", Content: "Execute your task now."},
+			{Role: "assistant", ToolCalls: []ToolCall{
+				{ID: "c1", Name: "kubectl.get", Args: args("api"), Seq: 1, Status: corev1alpha1.ActionStatusExecuted},
+				{ID: "c2", Name: "report.finding", Args: map[string]interface{}{"message": "api is degraded"}},
+			}},
+			{Role: "user", ToolResults: []ToolResult{{ToolCallID: "c1", Content: "api 0/3"}, {ToolCallID: "c2", Content: "recorded"}}},
+			{Role: "assistant", ToolCalls: []ToolCall{
+				{ID: "c3", Name: "kubectl.rollout", Args: args("api"), Seq: 2, Status: corev1alpha1.ActionStatusExecuted},
+			}},
+			{Role: "user", ToolResults: []ToolResult{{ToolCallID: "c3", Content: "restarted"}}},
+			{Role: "assistant", Content: "Restarted api."},
+		},
+	}
+}
+
+func TestPlaybackProvider(t *testing.T) {
+	p := NewPlaybackProvider(replayTestTranscript())
+	ctx := context.Background()
+
+	resp, err := p.Complete(ctx, &provider.CompletionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.ToolCalls) != 2 || resp.ToolCalls[0].Name != "kubectl.get" {
+		t.Errorf("unexpected first turn %+v", resp.ToolCalls)
+	}
+	if _, err := p.Complete(ctx, &provider.CompletionRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = p.Complete(ctx, &provider.CompletionRequest{})
+	if err != nil || resp.Content != "Restarted api." || resp.HasToolCalls() {
+		t.Errorf("unexpected final turn %+v (%v)", resp, err)
+	}
+	if _, err := p.Complete(ctx, &provider.CompletionRequest{}); !errors.Is(err, ErrPlaybackExhausted) {
+		t.Errorf("expected ErrPlaybackExhausted, got %v", err)
+	}
+	if len(p.Requests()) != 4 {
+		t.Errorf("expected 4 recorded requests, got %d", len(p.Requests()))
+	}
+}
+
+func TestRecordedTools(t *testing.T) {
+	screened := func(tool, output string) string {
+		block, _ := security.ScreenToolOutput(tool, output)
+		return block
+	}
+	args := map[string]interface{}{"resource": "deployment", "name": "api"}
+	tr := &Transcript{Messages: []Message{
+		{Role: "assistant", ToolCalls: []ToolCall{
+			{ID: "c1", Name: "kubectl.get", Args: args},
+			{ID: "c2", Name: "kubectl.get", Args: args},
+			{ID: "c3", Name: "kubectl.rollout", Args: args},
+		}},
+		{Role: "user", ToolResults: []ToolResult{
+			{ToolCallID: "c1", Content: screened("kubectl.get", "api 0/3")},
+			{ToolCallID: "c2", Content: screened("kubectl.get", "api 3/3")},
+			{ToolCallID: "c3", Content: "BLOCKED: outside change window", IsError: true},
+		}},
+	}}
+	reg := RecordedTools(tr)
+	ctx := context.Background()
+
+	for _, want := range []string{"api 0/3", "api 3/3", "api 3/3"} {
+		if got, err := reg.Execute(ctx, "kubectl.get", args); err != nil || got != want {
+			t.Errorf("kubectl.get = %q, %v; want %q", got, err, want)
+		}
+	}
+	if _, err := reg.Execute(ctx, "kubectl.get", map[string]interface{}{"resource": "pod"}); err == nil {
+		t.Error("expected an error for arguments that were never called")
+	}
+	if _, err := reg.Execute(ctx, "kubectl.rollout", args); err == nil {
+		t.Error("a call that never ran has no output to serve")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	recorded := replayTestTranscript()
+	replayed := &Transcript{Messages: []Message{
+		{Role: "assistant", ToolCalls: []ToolCall{
+			{ID: "c1", Name: "kubectl.get", Seq: 1, Status: corev1alpha1.ActionStatusExecuted},
+			{ID: "c2", Name: "report.finding"},
+		}},
+	}}
+
+	steps := Compare(recorded, replayed)
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps (report.finding skipped), got %d", len(steps))
+	}
+	if steps[0].Changed() {
+		t.Errorf("the read should be unchanged, got %s", steps[0].Replayed)
+	}
+	if !steps[1].Changed() || steps[1].Replayed != OutcomeNotReached {
+		t.Errorf("expected the rollout to be not-reached, got %s", steps[1].Replayed)
+	}
+
+	replayed.Messages = append(replayed.Messages, Message{Role: "assistant", ToolCalls: []ToolCall{
+		{ID: "c3", Name: "kubectl.rollout", Seq: 2, Status: corev1alpha1.ActionStatusSkipped, Reason: "already healthy"},
+	}})
+	steps = Compare(recorded, replayed)
+	if steps[1].Replayed != OutcomeSkipped || steps[1].Reason != "already healthy" {
+		t.Errorf("expected the rollout to be skipped with its reason, got %+v", steps[1])
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+
+const (
+	// DataKey is the ConfigMap key holding the transcript JSON.
+	DataKey = "transcript.json"
+
+	// maxStoredBytes keeps the transcript under the 1 MiB ConfigMap limit,
+	// leaving room for metadata.
+	maxStoredBytes = 900 * 1024
+)
+
+// outputCaps are the successively tighter limits applied to tool outputs
+// and message text when a transcript is too large to store.
+var outputCaps = []int{64 * 1024, 16 * 1024, 4 * 1024, 1024, 256}
+
+// ConfigMapName returns the name of the ConfigMap holding a run's transcript.
+func ConfigMapName(run string) string {
+	return run + "-transcript"
+}
+
+// Save writes the transcript to a ConfigMap owned by the run (so it is
+// deleted with it), shortening tool outputs if needed to fit.
+func Save(ctx context.Context, c client.Client, run *corev1alpha1.LegatorRun, t *Transcript) (*corev1alpha1.TranscriptRef, error) {
+	data, err := Encode(t)
+	if err != nil {
+		return nil, err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(run.Name),
+			Namespace: run.Namespace,
+			Labels: map[string]string{
+				"legator.io/agent": run.Spec.AgentRef,
+				"legator.io/run":   run.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: corev1alpha1.GroupVersion.String(),
+					Kind:       "LegatorRun",
+					Name:       run.Name,
+					UID:        run.UID,
+				},
+			},
+		},
+		Data: map[string]string{DataKey: data},
+	}
+	if err := c.Create(ctx, cm); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("create transcript ConfigMap: %w", err)
+		}
+		existing := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(cm), existing); err != nil {
+			return nil, fmt.Errorf("get transcript ConfigMap: %w", err)
+		}
+		existing.Data = cm.Data
+		if err := c.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("update transcript ConfigMap: %w", err)
+		}
+	}
+
+	return &corev1alpha1.TranscriptRef{
+		ConfigMap:        cm.Name,
+		SystemPromptHash: t.SystemPromptHash,
+		Truncated:        t.Truncated,
+	}, nil
+}
+
+// Load reads a transcript from its ConfigMap.
+func Load(ctx context.Context, c client.Client, namespace, configMap string) (*Transcript, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: configMap}, cm); err != nil {
+		return nil, fmt.Errorf("get transcript ConfigMap %q: %w", configMap, err)
+	}
+	data, ok := cm.Data[DataKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %q has no %s key", configMap, DataKey)
+	}
+	return Decode(data)
+}
+
+// Encode serializes a transcript, shortening tool outputs and message text
+// until it fits in a ConfigMap. Truncated is set if anything was shortened.
+func Encode(t *Transcript) (string, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("encode transcript: %w", err)
+	}
+	for _, limit := range outputCaps {
+		if len(data) <= maxStoredBytes {
+			break
+		}
+		t.Truncated = true
+		for i := range t.Messages {
+			m := &t.Messages[i]
+			m.Content = truncate(m.Content, limit)
+			for j := range m.ToolResults {
+				m.ToolResults[j].Content = truncate(m.ToolResults[j].Content, limit)
+			}
+		}
+		if data, err = json.Marshal(t); err != nil {
+			return "", fmt.Errorf("encode transcript: %w", err)
+		}
+	}
+	if len(data) > maxStoredBytes {
+		return "", fmt.Errorf("transcript too large to store (%d bytes)", len(data))
+	}
+	return string(data), nil
+}
+
+// Decode parses a stored transcript.
+func Decode(data string) (*Transcript, error) {
+	t := &Transcript{}
+	if err := json.Unmarshal([]byte(data), t); err != nil {
+		return nil, fmt.Errorf("decode transcript: %w", err)
+	}
+	if t.Version > Version {
+		return nil, fmt.Errorf("transcript version %d is newer than supported version %d", t.Version, Version)
+	}
+	return t, nil
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "... (truncated)"
+}
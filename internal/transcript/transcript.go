@@ -0,0 +1,194 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+// Package transcript captures the full conversation of an agent run — every
+// message, tool call and untruncated (but sanitized) tool output — so a run
+// can be inspected after the fact and replayed offline against changed
+// guardrails.
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+	"github.com/marcus-qen/legator/internal/security"
+)
+
+// Version is the transcript format version.
+const Version = 1
+
+// Transcript is the recorded conversation of one run.
+type Transcript struct {
+	Version int    `json:"version"`
+	Agent   string `json:"agent"`
+	Run     string `json:"run"`
+	Model   string `json:"model,omitempty"`
+
+	// SystemPromptHash identifies the system prompt without storing it.
+	SystemPromptHash string `json:"systemPromptHash"`
+
+	Messages []Message `json:"messages"`
+
+	// Truncated is set if tool outputs were shortened to fit storage.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Message is one conversation message.
+type Message struct {
+	Role        string       `json:"role"`
+	Content     string       `json:"content,omitempty"`
+	ToolCalls   []ToolCall   `json:"toolCalls,omitempty"`
+	ToolResults []ToolResult `json:"toolResults,omitempty"`
+}
+
+// ToolCall is a tool call made by the model, with the outcome the runner
+// recorded for it. Seq, Tier and Status are empty for calls that are not
+// recorded as actions (e.g. report.finding).
+type ToolCall struct {
+	ID   string                 `json:"id"`
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args,omitempty"`
+
+	Seq    int32                     `json:"seq,omitempty"`
+	Tier   corev1alpha1.ActionTier   `json:"tier,omitempty"`
+	Status corev1alpha1.ActionStatus `json:"status,omitempty"`
+
+	// Reason is why the call didn't run, if it was blocked, skipped or
+	// sent for approval.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ToolResult is the result returned to the model for a tool call.
+type ToolResult struct {
+	ToolCallID string `json:"toolCallId"`
+	Content    string `json:"content"`
+	IsError    bool   `json:"isError,omitempty"`
+}
+
+// HashPrompt returns the SHA-256 of a system prompt.
+func HashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// callRef locates a tool call within the transcript.
+type callRef struct {
+	message, call int
+}
+
+// Recorder accumulates a transcript while a run executes. Messages are
+// copied and sanitized as they are recorded, so later changes to the live
+// conversation (such as compaction) don't affect the transcript.
+//
+// A nil *Recorder is valid and records nothing.
+type Recorder struct {
+	t     Transcript
+	calls map[string]callRef
+}
+
+// NewRecorder starts a transcript for a run.
+func NewRecorder(agent, run, model, systemPrompt string) *Recorder {
+	return &Recorder{
+		t: Transcript{
+			Version:          Version,
+			Agent:            agent,
+			Run:              run,
+			Model:            model,
+			SystemPromptHash: HashPrompt(systemPrompt),
+		},
+		calls: make(map[string]callRef),
+	}
+}
+
+// Record appends a message.
+func (r *Recorder) Record(msg provider.Message) {
+	if r == nil {
+		return
+	}
+	m := Message{
+		Role:    msg.Role,
+		Content: security.Sanitize(msg.Content),
+	}
+	for _, tc := range msg.ToolCalls {
+		r.calls[tc.ID] = callRef{message: len(r.t.Messages), call: len(m.ToolCalls)}
+		m.ToolCalls = append(m.ToolCalls, ToolCall{
+			ID:   tc.ID,
+			Name: tc.Name,
+			Args: sanitizeArgs(tc.Args),
+		})
+	}
+	for _, tr := range msg.ToolResults {
+		m.ToolResults = append(m.ToolResults, ToolResult{
+			ToolCallID: tr.ToolCallID,
+			Content:    security.Sanitize(tr.Content),
+			IsError:    tr.IsError,
+		})
+	}
+	r.t.Messages = append(r.t.Messages, m)
+}
+
+// Outcome records the action the runner recorded for a tool call.
+func (r *Recorder) Outcome(toolCallID string, record corev1alpha1.ActionRecord) {
+	if r == nil {
+		return
+	}
+	ref, ok := r.calls[toolCallID]
+	if !ok {
+		return
+	}
+	tc := &r.t.Messages[ref.message].ToolCalls[ref.call]
+	tc.Seq = record.Seq
+	tc.Tier = record.Tier
+	tc.Status = record.Status
+	switch record.Status {
+	case corev1alpha1.ActionStatusBlocked, corev1alpha1.ActionStatusSkipped,
+		corev1alpha1.ActionStatusPendingApproval, corev1alpha1.ActionStatusDenied:
+		tc.Reason = record.Result
+	}
+}
+
+// Transcript returns the recorded transcript.
+func (r *Recorder) Transcript() *Transcript {
+	if r == nil {
+		return nil
+	}
+	return &r.t
+}
+
+// sanitizeArgs scrubs secrets from every string in tool call arguments.
+func sanitizeArgs(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		out[k] = sanitizeValue(v)
+	}
+	return out
+}
+
+func sanitizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return security.Sanitize(val)
+	case map[string]interface{}:
+		return sanitizeArgs(val)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i := range val {
+			out[i] = sanitizeValue(val[i])
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package transcript
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	corev1alpha1 "github.com/marcus-qen/legator/api/v1alpha1"
+	"github.com/marcus-qen/legator/internal/provider"
+)
+
+func TestRecorder_SanitizesAndCopies(t *testing.T) {
+	rec := NewRecorder("watchman", "watchman-abc12", "anthropic/claude", "system prompt")
+
+	args := map[string]interface{}{"command": "mysql --password: super-secret-123!"}
+	rec.Record(provider.Message{
+		Role:      "assistant",
+		ToolCalls: []provider.ToolCall{{ID: "c1", Name: "ssh.exec", Args: args}},
+	})
+	rec.Record(provider.Message{
+		Role:        "user",
+		ToolResults: []provider.ToolResult{{ToolCallID: "c1", Content: "password: super-secret-123!"}},
+	})
+	args["command"] = "changed"
+
+	tr := rec.Transcript()
+	if tr.SystemPromptHash != HashPrompt("system prompt") || !strings.HasPrefix(tr.SystemPromptHash, "sha256:") {
+		t.Errorf("unexpected prompt hash %q", tr.SystemPromptHash)
+	}
+	if len(tr.Messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(tr.Messages))
+	}
+	cmd := tr.Messages[0].ToolCalls[0].Args["command"].(string)
+	if strings.Contains(cmd, "super-secret") || cmd == "changed" {
+		t.Errorf("expected a sanitized copy of the args, got %q", cmd)
+	}
+	if strings.Contains(tr.Messages[1].ToolResults[0].Content, "super-secret") {
+		t.Errorf("tool output not sanitized: %q", tr.Messages[1].ToolResults[0].Content)
+	}
+}
+
+func TestRecorder_Outcome(t *testing.T) {
+	rec := NewRecorder("watchman", "watchman-abc12", "", "")
+	rec.Record(provider.Message{
+		Role: "assistant",
+		ToolCalls: []provider.ToolCall{
+			{ID: "c1", Name: "kubectl.get"},
+			{ID: "c2", Name: "report.finding"},
+		},
+	})
+	rec.Outcome("c1", corev1alpha1.ActionRecord{Seq: 1, Tier: corev1alpha1.ActionTierRead, Status: corev1alpha1.ActionStatusExecuted})
+	rec.Outcome("unknown", corev1alpha1.ActionRecord{Seq: 2})
+
+	calls := rec.Transcript().Messages[0].ToolCalls
+	if calls[0].Seq != 1 || calls[0].Status != corev1alpha1.ActionStatusExecuted {
+		t.Errorf("outcome not recorded: %+v", calls[0])
+	}
+	if calls[1].Status != "" {
+		t.Errorf("report.finding should have no outcome, got %+v", calls[1])
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var rec *Recorder
+	rec.Record(provider.Message{Role: "user"})
+	rec.Outcome("c1", corev1alpha1.ActionRecord{})
+	if rec.Transcript() != nil {
+		t.Error("nil recorder should have no transcript")
+	}
+}
+
+func TestEncode_TruncatesLargeOutputs(t *testing.T) {
+	tr := &Transcript{Version: Version}
+	for i := 0; i < 20; i++ {
+		tr.Messages = append(tr.Messages, Message{
+			Role:        "user",
+			ToolResults: []ToolResult{{ToolCallID: "c", Content: strings.Repeat("x", 100*1024)}},
+		})
+	}
+
+	data, err := Encode(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > maxStoredBytes {
+		t.Errorf("encoded transcript is %d bytes", len(data))
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Truncated {
+		t.Error("expected the transcript to be marked truncated")
+	}
+}
+
+func TestDecode_RejectsNewerVersion(t *testing.T) {
+	if _, err := Decode(`{"version": 99}`); err == nil {
+		t.Error("expected an error for a newer transcript version")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	run := &corev1alpha1.LegatorRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "watchman-abc12", Namespace: "agents", UID: "uid-1"},
+		Spec:       corev1alpha1.LegatorRunSpec{AgentRef: "watchman"},
+	}
+	rec := NewRecorder("watchman", run.Name, "", "prompt")
+	rec.Record(provider.Message{Role: "user", Content: "Execute your task now."})
+
+	ref, err := Save(ctx, c, run, rec.Transcript())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.ConfigMap != "watchman-abc12-transcript" || ref.SystemPromptHash != HashPrompt("prompt") {
+		t.Errorf("unexpected ref %+v", ref)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "agents", Name: ref.ConfigMap}, cm); err != nil {
+		t.Fatal(err)
+	}
+	if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].UID != "uid-1" {
+		t.Errorf("expected the ConfigMap to be owned by the run, got %+v", cm.OwnerReferences)
+	}
+
+	// Saving again updates in place
+	rec.Record(provider.Message{Role: "assistant", Content: "All healthy."})
+	if _, err := Save(ctx, c, run, rec.Transcript()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(ctx, c, "agents", ref.ConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Messages) != 2 || got.Messages[1].Content != "All healthy." {
+		t.Errorf("unexpected loaded transcript %+v", got.Messages)
+	}
+}